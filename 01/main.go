@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"github.com/SadikSunbul/Go-Elasticsearch/breaker"
+	"github.com/SadikSunbul/Go-Elasticsearch/retry"
 	"github.com/elastic/go-elasticsearch/v8"
 	"log"
 	"strings"
@@ -47,7 +49,7 @@ func ConnectElasticsearch() (*elasticsearch.Client, error) {
 	cfg := elasticsearch.Config{
 		Addresses: []string{"http://localhost:9200"},
 	}
-	es, err := elasticsearch.NewClient(cfg)
+	es, err := elasticsearch.NewClient(breaker.Configure(retry.Configure(cfg)))
 
 	return es, err
 }
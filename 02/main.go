@@ -2,13 +2,15 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
 	"strings"
 
+	"github.com/SadikSunbul/Go-Elasticsearch/breaker"
+	"github.com/SadikSunbul/Go-Elasticsearch/q"
+	"github.com/SadikSunbul/Go-Elasticsearch/retry"
 	"github.com/elastic/go-elasticsearch/v8"
-	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/core/search"
 )
 
 func main() {
@@ -24,9 +26,9 @@ func main() {
 }
 
 func ConnectToElasticsearch() (*elasticsearch.TypedClient, error) {
-	client, err := elasticsearch.NewTypedClient(elasticsearch.Config{
+	client, err := elasticsearch.NewTypedClient(breaker.Configure(retry.Configure(elasticsearch.Config{
 		Addresses: []string{"http://localhost:9200"},
-	})
+	})))
 	if err != nil {
 		log.Fatalf("İstemci bağlantı hatası: %v", err)
 	}
@@ -76,23 +78,16 @@ func CheckDocumentExists(client *elasticsearch.TypedClient) {
 }
 
 func SearchWithTermQuery(client *elasticsearch.TypedClient) {
-	// Term sorgusu oluştur
-	query := types.Query{
-		Term: map[string]types.TermQuery{
-			"name": {Value: "Foo"}, // "name" alanında "Foo" arar
-		},
-	}
-
-	// Sorguyu JSON'a çevir
-	queryJSON, err := json.Marshal(query)
+	// Term sorgusu oluştur ("name" alanında "Foo" arar)
+	query, err := q.Term("name", "Foo").Build()
 	if err != nil {
-		log.Fatalf("Sorgu JSON'a çevirme hatası: %v", err)
+		log.Fatalf("Sorgu hatası: %v", err)
 	}
 
 	// Arama isteği yap
 	res, err := client.Search().
 		Index("my_index").
-		Raw(strings.NewReader(string(queryJSON))).
+		Request(&search.Request{Query: query}).
 		Do(context.Background())
 	if err != nil {
 		log.Fatalf("Arama hatası: %v", err)
@@ -105,6 +100,11 @@ func SearchWithTermQuery(client *elasticsearch.TypedClient) {
 
 	fmt.Println("Arama başarılı, sonuç sayısı:", res.Hits.Total.Value)
 	for _, hit := range res.Hits.Hits {
-		fmt.Printf("ID: %s, Score: %f\n", hit.Id_, hit.Score_)
+		// Sıralamalı sorgularda skor hesaplanmaz, _score null döner
+		if hit.Score_ == nil {
+			fmt.Printf("ID: %s, Score: -\n", *hit.Id_)
+			continue
+		}
+		fmt.Printf("ID: %s, Score: %f\n", *hit.Id_, *hit.Score_)
 	}
 }
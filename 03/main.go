@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"log"
 
+	"github.com/SadikSunbul/Go-Elasticsearch/breaker"
+	"github.com/SadikSunbul/Go-Elasticsearch/retry"
 	"github.com/elastic/go-elasticsearch/v8"
 	"github.com/elastic/go-elasticsearch/v8/typedapi/core/search"
 	"github.com/elastic/go-elasticsearch/v8/typedapi/indices/create"
@@ -40,9 +42,9 @@ func main() {
 
 // Elasticsearch'e bağlanma fonksiyonu
 func ConnectToElasticsearch() (*elasticsearch.TypedClient, error) {
-	client, err := elasticsearch.NewTypedClient(elasticsearch.Config{
+	client, err := elasticsearch.NewTypedClient(breaker.Configure(retry.Configure(elasticsearch.Config{
 		Addresses: []string{"http://localhost:9200"},
-	})
+	})))
 	if err != nil {
 		log.Fatalf("İstemci bağlantı hatası: %v", err)
 	}
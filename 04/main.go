@@ -8,6 +8,8 @@ import (
 	"log"
 	"time"
 
+	"github.com/SadikSunbul/Go-Elasticsearch/breaker"
+	"github.com/SadikSunbul/Go-Elasticsearch/retry"
 	"github.com/elastic/go-elasticsearch/v8"
 )
 
@@ -32,7 +34,7 @@ func createESClient() (*elasticsearch.Client, error) {
 	cfg := elasticsearch.Config{
 		Addresses: []string{"http://localhost:9200"},
 	}
-	client, err := elasticsearch.NewClient(cfg)
+	client, err := elasticsearch.NewClient(breaker.Configure(retry.Configure(cfg)))
 	if err != nil {
 		return nil, err
 	}
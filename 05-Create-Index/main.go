@@ -6,15 +6,17 @@ import (
 	"fmt"
 	"log"
 
+	"github.com/SadikSunbul/Go-Elasticsearch/breaker"
+	"github.com/SadikSunbul/Go-Elasticsearch/retry"
 	"github.com/elastic/go-elasticsearch/v8"
 	"github.com/elastic/go-elasticsearch/v8/typedapi/indices/create"
 	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
 )
 
 func ConnectToElasticsearch() (*elasticsearch.TypedClient, error) {
-	return elasticsearch.NewTypedClient(elasticsearch.Config{
+	return elasticsearch.NewTypedClient(breaker.Configure(retry.Configure(elasticsearch.Config{
 		Addresses: []string{"http://localhost:9200"},
-	})
+	})))
 }
 
 func main() {
@@ -4,16 +4,20 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
 
+	"github.com/SadikSunbul/Go-Elasticsearch/breaker"
+	"github.com/SadikSunbul/Go-Elasticsearch/export"
+	"github.com/SadikSunbul/Go-Elasticsearch/retry"
 	"github.com/elastic/go-elasticsearch/v8"
 	"github.com/elastic/go-elasticsearch/v8/typedapi/indices/create"
 	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
 )
 
 func ConnectToElasticsearch() (*elasticsearch.TypedClient, error) {
-	return elasticsearch.NewTypedClient(elasticsearch.Config{
+	return elasticsearch.NewTypedClient(breaker.Configure(retry.Configure(elasticsearch.Config{
 		Addresses: []string{"http://localhost:9200"},
-	})
+	})))
 }
 
 func main() {
@@ -29,6 +33,7 @@ func main() {
 	// Normal object indeksi oluştur
 	//CreateAuthorIndex(es, "object_index")
 	//CreateAuthorDocument(es, "object_index")
+	//ExportAuthorDocuments(es, "object_index")
 
 	// Flattened object indeksi oluştur
 	//CreateFlattenedAuthorIndex(es, "flattened_object_index")
@@ -99,6 +104,27 @@ func CreateAuthorDocument(es *elasticsearch.TypedClient, indexName string) {
 	fmt.Printf("Shards: %+v\n", response.Shards_)
 }
 
+func ExportAuthorDocuments(es *elasticsearch.TypedClient, indexName string) {
+	/*
+		Object tipindeki alanlar dışa aktarılırken tam yoluyla (author.first_name) sütun olur
+		Belgeler PIT ve search_after ile sayfa sayfa okunur, indeks ne kadar büyük olursa olsun tamamı yazılır
+	*/
+	count, err := export.Export(es, os.Stdout, export.Options{
+		Index: indexName,
+		Columns: []export.Column{
+			{Field: "author.first_name", Header: "Ad"},
+			{Field: "author.last_name", Header: "Soyad"},
+			{Field: "sell_count", Header: "Satış"},
+		},
+		Format:  export.CSV,
+		Numbers: export.TurkishNumbers,
+	})
+	if err != nil {
+		log.Fatalf("Dışa aktarma hatası: %v", err)
+	}
+	fmt.Printf("%d döküman dışa aktarıldı\n", count)
+}
+
 func CreateFlattenedAuthorIndex(es *elasticsearch.TypedClient, indexName string) {
 	// Önce varolan indeksi sil
 	_, err := es.Indices.Delete(indexName).
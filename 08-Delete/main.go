@@ -5,13 +5,16 @@ import (
 	"fmt"
 	"log"
 
+	"github.com/SadikSunbul/Go-Elasticsearch/breaker"
+	"github.com/SadikSunbul/Go-Elasticsearch/dlq"
+	"github.com/SadikSunbul/Go-Elasticsearch/retry"
 	"github.com/elastic/go-elasticsearch/v8"
 )
 
 func ConnectToElasticsearch() (*elasticsearch.TypedClient, error) {
-	return elasticsearch.NewTypedClient(elasticsearch.Config{
+	return elasticsearch.NewTypedClient(breaker.Configure(retry.Configure(elasticsearch.Config{
 		Addresses: []string{"http://localhost:9200"},
-	})
+	})))
 }
 
 func main() {
@@ -24,9 +27,20 @@ func main() {
 
 	fmt.Println("*......Connec to Elasticsearch is success......*")
 
+	// Eklenemeyen dökümanlar kaybolmaz, failed_documents.ndjson dosyasına yazılır
+	// "go run . dlq replay 08-Delete/failed_documents.ndjson" ile tekrar gönderilebilir
+	sink, err := dlq.NewFileSink("failed_documents.ndjson")
+	if err != nil {
+		log.Fatal("dead-letter dosyası açılamadı:", err)
+	}
+	defer sink.Close()
+
 	// Döküman işlemleri
 	CreateIndex(es, "my_index")
-	documentIDs := AddDocuments(es, "my_index")
+	documentIDs := AddDocuments(es, "my_index", sink)
+	if len(documentIDs) == 0 {
+		log.Fatal("hiçbir döküman eklenemedi, bkz. failed_documents.ndjson")
+	}
 	DeleteDocument(es, "my_index", documentIDs[0])
 	DeleteNonExistentDocument(es, "my_index", "id")
 }
@@ -47,7 +61,8 @@ func CreateIndex(es *elasticsearch.TypedClient, indexName string) {
 	fmt.Println("indeks başarıyla oluşturuldu")
 }
 
-func AddDocuments(es *elasticsearch.TypedClient, indexName string) []string {
+// AddDocuments, örnek dökümanları ekler; eklenemeyenleri hatasıyla birlikte sink'e yazar
+func AddDocuments(es *elasticsearch.TypedClient, indexName string, sink dlq.Sink) []string {
 	// Örnek dökümanlar
 	documents := []map[string]interface{}{
 		{
@@ -75,7 +90,11 @@ func AddDocuments(es *elasticsearch.TypedClient, indexName string) []string {
 
 		_, err := es.Index(indexName).Id(docID).Document(doc).Do(context.Background())
 		if err != nil {
-			log.Fatal("döküman ekleme hatası:", err)
+			log.Printf("döküman ekleme hatası, dead-letter'a yazılıyor: %v", err)
+			if sinkErr := sink.Write(dlq.NewEntry(indexName, docID, doc, err, 1)); sinkErr != nil {
+				log.Fatal("dead-letter yazma hatası:", sinkErr)
+			}
+			continue
 		}
 		documentIDs = append(documentIDs, docID)
 		fmt.Printf("Döküman eklendi, ID: %s\n", docID)
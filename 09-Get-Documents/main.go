@@ -6,14 +6,23 @@ import (
 	"fmt"
 	"io/ioutil"
 	"log"
+	"os"
 
+	"github.com/SadikSunbul/Go-Elasticsearch/breaker"
+	"github.com/SadikSunbul/Go-Elasticsearch/dlq"
+	"github.com/SadikSunbul/Go-Elasticsearch/indexops"
+	"github.com/SadikSunbul/Go-Elasticsearch/retry"
+	"github.com/SadikSunbul/Go-Elasticsearch/transform"
 	"github.com/elastic/go-elasticsearch/v8"
 )
 
+// esBreaker, istemcinin breaker'ıdır; yükleme sonunda durumu yazdırılır
+var esBreaker = breaker.New(breaker.DefaultThreshold, breaker.DefaultCooldown)
+
 func ConnectToElasticsearch() (*elasticsearch.TypedClient, error) {
-	return elasticsearch.NewTypedClient(elasticsearch.Config{
+	return elasticsearch.NewTypedClient(breaker.ConfigureWith(retry.Configure(elasticsearch.Config{
 		Addresses: []string{"http://localhost:9200"},
-	})
+	}), esBreaker))
 }
 
 func main() {
@@ -45,23 +54,86 @@ func main() {
 	}
 
 	// JSON verilerini parse et
-	var documents []map[string]interface{}
-	if err := json.Unmarshal(data, &documents); err != nil {
+	var records []transform.Record
+	if err := json.Unmarshal(data, &records); err != nil {
 		log.Fatal("JSON parse hatası:", err)
 	}
 
+	// Kayıtları indekslemeden önce düzelt; geçersiz olanlar rejected_documents.ndjson dosyasına yazılır
+	rejects, err := os.Create("rejected_documents.ndjson")
+	if err != nil {
+		log.Fatal("Dead-letter dosyası oluşturma hatası:", err)
+	}
+	defer rejects.Close()
+
+	transformed, err := transform.DocumentImport().Run(records, rejects)
+	if err != nil {
+		log.Fatal("Dönüştürme hatası:", err)
+	}
+	fmt.Println("Dönüştürme:", transformed)
+
+	// Geçerli olduğu halde indekslenemeyen belgeler (ağ, mapping, 429...) failed_documents.ndjson dosyasına yazılır
+	sink, err := dlq.NewFileSink("failed_documents.ndjson")
+	if err != nil {
+		log.Fatal("Dead-letter dosyası açma hatası:", err)
+	}
+	defer sink.Close()
+
 	// Her belgeyi Elasticsearch'e ekle
+	// Yükleme sırasında refresh kapatılır, bittiğinde önceki değer geri yüklenip indeks bir kez refresh edilir
 	var documentIDs []string
-	for _, doc := range documents {
-		resp, err := es.Index("my_index").Document(doc).Do(ctx)
-		if err != nil {
-			log.Printf("Belge ekleme hatası: %v", err)
-			continue
+	err = indexops.WithRefreshDisabled(es, "my_index", func() error {
+		for _, record := range transformed.Accepted {
+			// ID kayıttan türetildiği için dosya tekrar yüklendiğinde aynı belgelerin üzerine yazılır
+			id, doc := record.Split()
+			resp, err := es.Index("my_index").Id(id).Document(doc).Do(ctx)
+			if err != nil {
+				log.Printf("Belge ekleme hatası: %v", err)
+				if sinkErr := sink.Write(dlq.NewEntry("my_index", id, doc, err, 1)); sinkErr != nil {
+					return sinkErr
+				}
+				continue
+			}
+			documentIDs = append(documentIDs, resp.Id_)
 		}
-		documentIDs = append(documentIDs, resp.Id_)
+		return nil
+	})
+	if err != nil {
+		log.Fatal("Toplu yükleme hatası:", err)
 	}
 
 	fmt.Printf("Eklenen belge ID'leri: %v\n", documentIDs)
+	if failed := sink.Count(); failed > 0 {
+		fmt.Printf("%d belge eklenemedi, bkz. failed_documents.ndjson\n", failed)
+	}
+	fmt.Printf("Tekrar denemeler: %s\n", retry.DefaultMetrics.Snapshot())
+	fmt.Printf("Circuit breaker: %s\n", esBreaker.Snapshot())
+
+	// Yükleme bitti: segmentleri birleştir ve indeksi yazmaya kapat
+	if result, err := indexops.ForceMerge(es, "my_index", 1); err != nil {
+		log.Printf("Forcemerge hatası: %v", err)
+	} else {
+		fmt.Println(result)
+	}
+	if result, err := indexops.AddBlock(es, "my_index", indexops.Write); err != nil {
+		log.Printf("Blok ekleme hatası: %v", err)
+	} else {
+		fmt.Println(result)
+	}
+
+	statuses, err := indexops.Status(es, "my_index")
+	if err != nil {
+		log.Printf("Durum okuma hatası: %v", err)
+	}
+	for _, status := range statuses {
+		fmt.Println(status)
+	}
+
+	if result, err := indexops.RemoveBlock(es, "my_index", indexops.Write); err != nil {
+		log.Printf("Blok kaldırma hatası: %v", err)
+	} else {
+		fmt.Println(result)
+	}
 
 	// İlk belgeyi getir
 	if len(documentIDs) > 0 {
@@ -69,7 +141,7 @@ func main() {
 		if err != nil {
 			log.Printf("Belge getirme hatası: %s", err)
 		} else {
-			fmt.Printf("İlk belge: %+s\n", resp)
+			fmt.Printf("İlk belge: %+v\n", resp)
 		}
 	}
 }
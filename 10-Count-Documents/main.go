@@ -5,13 +5,16 @@ import (
 	"fmt"
 	"log"
 
+	"github.com/SadikSunbul/Go-Elasticsearch/breaker"
+	"github.com/SadikSunbul/Go-Elasticsearch/q"
+	"github.com/SadikSunbul/Go-Elasticsearch/retry"
 	"github.com/elastic/go-elasticsearch/v8"
 )
 
 func ConnectToElasticsearch() (*elasticsearch.TypedClient, error) {
-	return elasticsearch.NewTypedClient(elasticsearch.Config{
+	return elasticsearch.NewTypedClient(breaker.Configure(retry.Configure(elasticsearch.Config{
 		Addresses: []string{"http://localhost:9200"},
-	})
+	})))
 }
 
 func main() {
@@ -76,9 +79,25 @@ func main() {
 	}
 	fmt.Printf("İndeksteki toplam belge sayısı: %d\n", count.Count)
 
-	count, err = es.Count().Index("my_index").Do(ctx)
+	// Sadece 24 Eylül 2024 tarihli belgeleri say (arama ile aynı sorgu DSL'i kullanılır)
+	dayQuery, err := q.Term("created_on", "2024-09-24").Build()
+	if err != nil {
+		log.Fatal("Sorgu hatası:", err)
+	}
+	count, err = es.Count().Index("my_index").Query(dayQuery).Do(ctx)
 	if err != nil {
 		log.Fatal("Filtreli sayma hatası:", err)
 	}
 	fmt.Printf("24 Eylül 2024 tarihli belge sayısı: %d\n", count.Count)
+
+	// terminate_after: her shard'da ilk eşleşmede durur, sadece "en az bir belge var mı" kontrolü için ucuzdur
+	sinceQuery, err := q.Range("created_on").Gte("2024-09-23").Build()
+	if err != nil {
+		log.Fatal("Sorgu hatası:", err)
+	}
+	count, err = es.Count().Index("my_index").Query(sinceQuery).TerminateAfter("1").Do(ctx)
+	if err != nil {
+		log.Fatal("Varlık kontrolü hatası:", err)
+	}
+	fmt.Printf("23 Eylül 2024 ve sonrası tarihli belge var mı: %t\n", count.Count > 0)
 }
@@ -8,7 +8,13 @@ import (
 	"os"
 	"strings"
 
+	"github.com/SadikSunbul/Go-Elasticsearch/breaker"
+	"github.com/SadikSunbul/Go-Elasticsearch/dlq"
+	"github.com/SadikSunbul/Go-Elasticsearch/retry"
+	"github.com/SadikSunbul/Go-Elasticsearch/transform"
 	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
 )
 
 func main() {
@@ -16,7 +22,7 @@ func main() {
 	cfg := elasticsearch.Config{
 		Addresses: []string{"http://localhost:9200"},
 	}
-	es, err := elasticsearch.NewClient(cfg)
+	es, err := elasticsearch.NewClient(breaker.Configure(retry.Configure(cfg)))
 	if err != nil {
 		log.Fatalf("Elasticsearch istemcisi oluşturulamadı: %s", err)
 	}
@@ -42,16 +48,39 @@ func main() {
 	}
 
 	// JSON verilerini ayrıştır
-	var documents []map[string]interface{}
-	if err := json.Unmarshal(data, &documents); err != nil {
+	var records []transform.Record
+	if err := json.Unmarshal(data, &records); err != nil {
 		log.Fatalf("JSON ayrıştırılamadı: %s", err)
 	}
 
+	// Kayıtları düzelt; geçersiz olanlar rejected_documents.ndjson dosyasına yazılır
+	rejects, err := os.Create("rejected_documents.ndjson")
+	if err != nil {
+		log.Fatalf("Dead-letter dosyası oluşturulamadı: %s", err)
+	}
+	defer rejects.Close()
+
+	transformed, err := transform.DocumentImport().Run(records, rejects)
+	if err != nil {
+		log.Fatalf("Kayıtlar dönüştürülemedi: %s", err)
+	}
+	fmt.Println("Dönüştürme:", transformed)
+
+	// İndekslenemeyen belgeler hatasıyla birlikte failed_documents.ndjson dosyasına yazılır
+	sink, err := dlq.NewFileSink("failed_documents.ndjson")
+	if err != nil {
+		log.Fatalf("Dead-letter dosyası açılamadı: %s", err)
+	}
+	defer sink.Close()
+
 	// Belge ID'lerini saklamak için dizi
 	var documentIDs []string
 
 	// Her belgeyi indeksle
-	for _, doc := range documents {
+	for _, record := range transformed.Accepted {
+		// Belgenin ID'si kayıttan türetilir, _source'a yazılmaz
+		id, doc := record.Split()
+
 		// Belgeyi JSON formatına dönüştür
 		docJSON, err := json.Marshal(doc)
 		if err != nil {
@@ -59,14 +88,21 @@ func main() {
 			continue
 		}
 
-		// Belgeyi indeksle
+		// Belgeyi indeksle; 429/503 gibi hatalarda retry transport'u tekrar dener
 		res, err := es.Index(
 			"my_index",
 			strings.NewReader(string(docJSON)),
+			es.Index.WithDocumentID(id),
 			es.Index.WithContext(context.Background()),
 		)
+		if err == nil && res.IsError() {
+			err = responseError(res)
+		}
 		if err != nil {
 			log.Printf("Belge indekslenemedi: %s", err)
+			if sinkErr := sink.Write(dlq.NewEntry("my_index", id, docJSON, err, 1)); sinkErr != nil {
+				log.Fatalf("Dead-letter dosyasına yazılamadı: %s", sinkErr)
+			}
 			continue
 		}
 		defer res.Body.Close()
@@ -85,6 +121,9 @@ func main() {
 	}
 
 	fmt.Println("Belge ID'leri:", documentIDs)
+	if failed := sink.Count(); failed > 0 {
+		fmt.Printf("%d belge indekslenemedi, bkz. failed_documents.ndjson\n", failed)
+	}
 
 	// İndeks varlığını kontrol et
 	exists, err := es.Indices.Exists([]string{"my_index"})
@@ -109,3 +148,18 @@ func main() {
 		fmt.Println("Belge mevcut mu:", docExists.StatusCode == 200)
 	}
 }
+
+// responseError, hata yanıtını tipli istemcinin döndüğü *types.ElasticsearchError'a çevirir
+// Böylece dead-letter kaydında hata türü (mapping, 429, version conflict) ayırt edilebilir
+func responseError(res *esapi.Response) error {
+	defer res.Body.Close()
+
+	esErr := types.NewElasticsearchError()
+	if err := json.NewDecoder(res.Body).Decode(esErr); err != nil {
+		return fmt.Errorf("HTTP %d: yanıt ayrıştırılamadı: %w", res.StatusCode, err)
+	}
+	if esErr.Status == 0 {
+		esErr.Status = res.StatusCode
+	}
+	return esErr
+}
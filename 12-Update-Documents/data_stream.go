@@ -0,0 +1,312 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/elastic/go-elasticsearch/v8"
+)
+
+/*
+	Data Stream:
+	created_on alanlı Document'ler aslında sadece eklenen olaylardır; güncellenmez, zamanla eskir ve silinir
+	Bu tür veriler my_index gibi tek bir indeks yerine bir data stream'e yazılır:
+
+	document-events (data stream)
+	  ├── .ds-document-events-2024.09.22-000001  (eski backing indeks, sadece okunur)
+	  └── .ds-document-events-2024.09.23-000002  (yazma indeksi)
+
+	- Data stream, data_stream: {} içeren bir index template'ten ilk yazmada (ya da CreateDataStream ile) oluşur
+	- Her belgede @timestamp alanı zorunludur
+	- Yazma sadece op_type=create ile yapılır; belge ID'si ile güncelleme (update/upsert) desteklenmez
+	- Arama data stream adıyla yapılır ve tüm backing indekslerde çalışır
+	- Rollover, yeni bir backing indeks oluşturur ve yazmayı ona geçirir (ILM ile otomatik yapılabilir)
+*/
+
+// dataStreamName, Document olaylarının yazıldığı data stream'dir
+// Yerleşik "logs-*-*" gibi template'lerle ve events-* rollover indeksleriyle çakışmayacak bir ad seçildi
+const dataStreamName = "document-events"
+
+// errDataStreamUpdate, data stream'e update ya da upsert yapılmak istendiğinde döner
+var errDataStreamUpdate = errors.New("data stream'ler sadece ekleme (op_type=create) kabul eder, update/upsert yapılamaz")
+
+// DocumentEvent, data stream'e yazılan Document'tir; @timestamp data stream için zorunludur
+type DocumentEvent struct {
+	Timestamp string `json:"@timestamp"`
+	Document
+}
+
+// DataStreamInfo, GET _data_stream yanıtındaki bir data stream'dir
+type DataStreamInfo struct {
+	Name           string `json:"name"`
+	Generation     int    `json:"generation"`
+	Status         string `json:"status"` // green, yellow, red
+	Template       string `json:"template"`
+	IlmPolicy      string `json:"ilm_policy"`
+	TimestampField struct {
+		Name string `json:"name"`
+	} `json:"timestamp_field"`
+	Indices []struct {
+		IndexName string `json:"index_name"`
+		IndexUUID string `json:"index_uuid"`
+	} `json:"indices"`
+}
+
+// createDocumentDataStream, data stream template'ini yazar ve data stream'i oluşturur
+func createDocumentDataStream(es *elasticsearch.Client) {
+	fmt.Println("\nData stream oluşturma:")
+
+	// data_stream: {} bu template ile eşleşen adların indeks yerine data stream olarak oluşturulmasını sağlar
+	// Öncelik, yerleşik template'lerden (100) ve uygulamanın template'lerinden (200) yüksek tutuldu
+	template := map[string]interface{}{
+		"index_patterns": []string{dataStreamName + "*"},
+		"data_stream":    map[string]interface{}{},
+		"priority":       300,
+		"template": map[string]interface{}{
+			"mappings": map[string]interface{}{
+				"properties": map[string]interface{}{
+					"@timestamp": map[string]interface{}{"type": "date"},
+					"title":      map[string]interface{}{"type": "text"},
+					"text":       map[string]interface{}{"type": "text"},
+					"created_on": map[string]interface{}{"type": "date", "format": "yyyy-MM-dd"},
+				},
+			},
+		},
+	}
+	templateJSON, err := json.Marshal(template)
+	if err != nil {
+		log.Fatalf("Template JSON'a dönüştürülemedi: %s", err)
+	}
+
+	res, err := es.Indices.PutIndexTemplate(
+		dataStreamName,
+		bytes.NewReader(templateJSON),
+		es.Indices.PutIndexTemplate.WithContext(context.Background()),
+	)
+	if err != nil {
+		log.Fatalf("Data stream template'i yazılamadı: %s", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		log.Fatalf("Data stream template'i yazılırken hata oluştu: %s", res.String())
+	}
+
+	// Data stream ilk yazmada da oluşurdu; açıkça oluşturmak template hatalarını erken gösterir
+	res, err = es.Indices.CreateDataStream(
+		dataStreamName,
+		es.Indices.CreateDataStream.WithContext(context.Background()),
+	)
+	if err != nil {
+		log.Fatalf("Data stream oluşturulamadı: %s", err)
+	}
+	defer res.Body.Close()
+
+	// Data stream zaten varsa 400 resource_already_exists_exception döner
+	if res.IsError() && !strings.Contains(res.String(), "resource_already_exists_exception") {
+		log.Fatalf("Data stream oluşturulurken hata oluştu: %s", res.String())
+	}
+
+	fmt.Printf("Data stream hazır: %s\n", dataStreamName)
+}
+
+// appendDocument, belgeyi op_type=create ile data stream'e ekler ve yazıldığı backing indeksi döner
+func appendDocument(dataStream string, doc Document, es *elasticsearch.Client) string {
+	// created_on olayın zamanıdır, @timestamp olarak da kullanılır
+	event := DocumentEvent{Timestamp: doc.CreatedOn, Document: doc}
+	eventJSON, err := json.Marshal(event)
+	if err != nil {
+		log.Fatalf("Belge JSON'a dönüştürülemedi: %s", err)
+	}
+
+	res, err := es.Index(
+		dataStream,
+		bytes.NewReader(eventJSON),
+		es.Index.WithOpType("create"),
+		es.Index.WithContext(context.Background()),
+	)
+	if err != nil {
+		log.Fatalf("Belge data stream'e eklenemedi: %s", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		log.Fatalf("Belge data stream'e eklenirken hata oluştu: %s", res.String())
+	}
+
+	var result UpdateResponse
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		log.Fatalf("Yanıt ayrıştırılamadı: %s", err)
+	}
+
+	fmt.Printf("Belge eklendi, ID: %s, backing indeks: %s\n", result.ID, result.Index)
+	return result.Index
+}
+
+// searchDataStream, data stream'deki belgeleri @timestamp'e göre yeniden eskiye listeler
+func searchDataStream(dataStream string, es *elasticsearch.Client) {
+	fmt.Println("\nData stream'de arama:")
+
+	// Yeni eklenen belgelerin aramada görünmesi için refresh yapılır
+	refreshRes, err := es.Indices.Refresh(
+		es.Indices.Refresh.WithIndex(dataStream),
+		es.Indices.Refresh.WithContext(context.Background()),
+	)
+	if err != nil {
+		log.Fatalf("Data stream refresh edilemedi: %s", err)
+	}
+	refreshRes.Body.Close()
+
+	query := map[string]interface{}{
+		"query": map[string]interface{}{
+			"range": map[string]interface{}{
+				"@timestamp": map[string]interface{}{
+					"gte": "2024-09-01",
+				},
+			},
+		},
+		"sort": []interface{}{
+			map[string]interface{}{"@timestamp": "desc"},
+		},
+	}
+	queryJSON, err := json.Marshal(query)
+	if err != nil {
+		log.Fatalf("Sorgu JSON'a dönüştürülemedi: %s", err)
+	}
+
+	res, err := es.Search(
+		es.Search.WithIndex(dataStream),
+		es.Search.WithBody(bytes.NewReader(queryJSON)),
+		es.Search.WithContext(context.Background()),
+	)
+	if err != nil {
+		log.Fatalf("Arama yapılamadı: %s", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		log.Fatalf("Arama sırasında hata oluştu: %s", res.String())
+	}
+
+	var searchResp struct {
+		Hits struct {
+			Hits []struct {
+				Index  string        `json:"_index"`
+				ID     string        `json:"_id"`
+				Source DocumentEvent `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&searchResp); err != nil {
+		log.Fatalf("Arama yanıtı ayrıştırılamadı: %s", err)
+	}
+
+	for _, hit := range searchResp.Hits.Hits {
+		fmt.Printf("%s [%s] %s: %s\n", hit.Source.Timestamp, hit.Index, hit.ID, hit.Source.Title)
+	}
+}
+
+// getDataStream, data stream'in bilgilerini getirir; data stream yoksa nil döner
+func getDataStream(name string, es *elasticsearch.Client) (*DataStreamInfo, error) {
+	res, err := es.Indices.GetDataStream(
+		es.Indices.GetDataStream.WithName(name),
+		es.Indices.GetDataStream.WithContext(context.Background()),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if res.IsError() {
+		return nil, fmt.Errorf("data stream bilgisi alınamadı: %s", res.String())
+	}
+
+	var resp struct {
+		DataStreams []DataStreamInfo `json:"data_streams"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&resp); err != nil {
+		return nil, err
+	}
+	for _, dataStream := range resp.DataStreams {
+		if dataStream.Name == name {
+			return &dataStream, nil
+		}
+	}
+	return nil, nil
+}
+
+// printBackingIndices, data stream'in backing indekslerini eskiden yeniye yazar; sonuncusu yazma indeksidir
+func printBackingIndices(dataStream string, es *elasticsearch.Client) {
+	fmt.Println("\nData stream backing indeksleri:")
+
+	info, err := getDataStream(dataStream, es)
+	if err != nil {
+		log.Fatalf("Data stream bilgisi alınamadı: %s", err)
+	}
+	if info == nil {
+		fmt.Printf("%s adında bir data stream yok\n", dataStream)
+		return
+	}
+
+	fmt.Printf("%s: generation %d, durum %s, template %s, zaman alanı %s\n",
+		info.Name, info.Generation, info.Status, info.Template, info.TimestampField.Name)
+	for i, index := range info.Indices {
+		role := ""
+		if i == len(info.Indices)-1 {
+			role = " (yazma indeksi)"
+		}
+		fmt.Printf("  %s%s\n", index.IndexName, role)
+	}
+}
+
+// rolloverDataStream, data stream için yeni bir backing indeks oluşturur ve yazmayı ona geçirir
+func rolloverDataStream(dataStream string, es *elasticsearch.Client) {
+	fmt.Println("\nData stream rollover:")
+
+	res, err := es.Indices.Rollover(
+		dataStream,
+		es.Indices.Rollover.WithContext(context.Background()),
+	)
+	if err != nil {
+		log.Fatalf("Rollover yapılamadı: %s", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		log.Fatalf("Rollover sırasında hata oluştu: %s", res.String())
+	}
+
+	var rolloverResp struct {
+		OldIndex   string `json:"old_index"`
+		NewIndex   string `json:"new_index"`
+		RolledOver bool   `json:"rolled_over"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&rolloverResp); err != nil {
+		log.Fatalf("Rollover yanıtı ayrıştırılamadı: %s", err)
+	}
+
+	fmt.Printf("%s -> %s (rollover: %v)\n", rolloverResp.OldIndex, rolloverResp.NewIndex, rolloverResp.RolledOver)
+}
+
+// checkUpdatable, indexName bir data stream ise errDataStreamUpdate döner
+// Update API data stream'e gönderildiğinde Elasticsearch'ün hatası nedenini açıkça söylemediği için
+// güncelleme yardımcıları istek göndermeden önce bunu kontrol eder
+func checkUpdatable(indexName string, es *elasticsearch.Client) error {
+	info, err := getDataStream(indexName, es)
+	if err != nil {
+		return err
+	}
+	if info != nil {
+		return fmt.Errorf("%s: %w", indexName, errDataStreamUpdate)
+	}
+	return nil
+}
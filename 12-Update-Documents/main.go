@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"log"
 
+	"github.com/SadikSunbul/Go-Elasticsearch/breaker"
+	"github.com/SadikSunbul/Go-Elasticsearch/retry"
 	"github.com/elastic/go-elasticsearch/v8"
 )
 
@@ -44,7 +46,7 @@ func main() {
 	cfg := elasticsearch.Config{
 		Addresses: []string{"http://localhost:9200"},
 	}
-	es, err := elasticsearch.NewClient(cfg)
+	es, err := elasticsearch.NewClient(breaker.Configure(retry.Configure(cfg)))
 	if err != nil {
 		log.Fatalf("Elasticsearch istemcisi oluşturulamadı: %s", err)
 	}
@@ -120,12 +122,50 @@ func main() {
 	countDocuments(indexName, es)
 
 	fmt.Scanf("devam etmek için enter tuşuna basınız")
+
+	// Tarih aralığına göre belge sayısını kontrol et
+	countDocumentsCreatedInMonth("2024-09-01", indexName, es)
+
+	fmt.Scanf("devam etmek için enter tuşuna basınız")
+
+	// 3. Document'leri data stream'e olay olarak ekle
+	createDocumentDataStream(es)
+	appendDocument(dataStreamName, doc, es)
+	appendDocument(dataStreamName, Document{
+		Title:     "İkinci Belge",
+		Text:      "Bu ikinci örnek belge metnidir.",
+		CreatedOn: "2024-09-23",
+	}, es)
+
+	fmt.Scanf("devam etmek için enter tuşuna basınız")
+
+	// Rollover sonrası yeni belgeler yeni backing indekse yazılır, arama ikisini de kapsar
+	rolloverDataStream(dataStreamName, es)
+	appendDocument(dataStreamName, Document{
+		Title:     "Üçüncü Belge",
+		Text:      "Bu üçüncü örnek belge metnidir.",
+		CreatedOn: "2024-09-24",
+	}, es)
+	searchDataStream(dataStreamName, es)
+	printBackingIndices(dataStreamName, es)
+
+	fmt.Scanf("devam etmek için enter tuşuna basınız")
+
+	// Data stream'e update/upsert reddedilir
+	if err := checkUpdatable(dataStreamName, es); err != nil {
+		fmt.Printf("\nBeklenen hata: %s\n", err)
+	}
 }
 
 // updateField, mevcut bir alanı günceller
 func updateField(documentID, indexName string, es *elasticsearch.Client) {
 	fmt.Println("\n1.1 Mevcut bir alanı güncelleme:")
 
+	// Data stream'lerde belge güncellenemez
+	if err := checkUpdatable(indexName, es); err != nil {
+		log.Fatalf("Güncelleme yapılamaz: %s", err)
+	}
+
 	// Güncelleme isteği oluştur
 	updateBody := map[string]interface{}{
 		"script": map[string]interface{}{
@@ -171,6 +211,11 @@ func updateField(documentID, indexName string, es *elasticsearch.Client) {
 func addNewFieldWithScript(documentID, indexName string, es *elasticsearch.Client) {
 	fmt.Println("\n1.2.1 Script kullanarak yeni bir alan ekleme:")
 
+	// Data stream'lerde belge güncellenemez
+	if err := checkUpdatable(indexName, es); err != nil {
+		log.Fatalf("Güncelleme yapılamaz: %s", err)
+	}
+
 	// Güncelleme isteği oluştur
 	updateBody := map[string]interface{}{
 		"script": map[string]interface{}{
@@ -213,6 +258,11 @@ func addNewFieldWithScript(documentID, indexName string, es *elasticsearch.Clien
 func addNewFieldWithDoc(documentID, indexName string, es *elasticsearch.Client) {
 	fmt.Println("\n1.2.2 Doc kullanarak yeni bir alan ekleme:")
 
+	// Data stream'lerde belge güncellenemez
+	if err := checkUpdatable(indexName, es); err != nil {
+		log.Fatalf("Güncelleme yapılamaz: %s", err)
+	}
+
 	// Güncelleme isteği oluştur
 	updateBody := map[string]interface{}{
 		"doc": map[string]interface{}{
@@ -255,6 +305,11 @@ func addNewFieldWithDoc(documentID, indexName string, es *elasticsearch.Client)
 func removeField(documentID, indexName string, es *elasticsearch.Client) {
 	fmt.Println("\n1.3 Bir alanı kaldırma:")
 
+	// Data stream'lerde belge güncellenemez
+	if err := checkUpdatable(indexName, es); err != nil {
+		log.Fatalf("Güncelleme yapılamaz: %s", err)
+	}
+
 	// Güncelleme isteği oluştur
 	updateBody := map[string]interface{}{
 		"script": map[string]interface{}{
@@ -297,6 +352,11 @@ func removeField(documentID, indexName string, es *elasticsearch.Client) {
 func upsertNonExistentDocument(indexName string, es *elasticsearch.Client) {
 	fmt.Println("\n2. Olmayan bir belgeyi ekleme (upsert):")
 
+	// Data stream'lerde belge güncellenemez
+	if err := checkUpdatable(indexName, es); err != nil {
+		log.Fatalf("Upsert yapılamaz: %s", err)
+	}
+
 	// Upsert isteği oluştur
 	upsertBody := map[string]interface{}{
 		"doc": map[string]interface{}{
@@ -391,3 +451,51 @@ func countDocuments(indexName string, es *elasticsearch.Client) {
 
 	fmt.Printf("Belge sayısı: %d\n", countResp.Count)
 }
+
+// countDocumentsCreatedInMonth, created_on alanı verilen ayın içinde olan belgeleri sayar
+// created_on "yyyy-MM-dd" biçiminde string olarak gönderilir, Elasticsearch bunu date alanı olarak indeksler
+func countDocumentsCreatedInMonth(month, indexName string, es *elasticsearch.Client) {
+	fmt.Println("\nTarih aralığına göre belge sayısını kontrol etme:")
+
+	// "2024-09-01||/M" ayın başı, "2024-09-01||+1M/M" bir sonraki ayın başıdır (date math)
+	// time_zone, ay sınırlarının İstanbul saatine göre hesaplanmasını sağlar
+	query := map[string]interface{}{
+		"query": map[string]interface{}{
+			"range": map[string]interface{}{
+				"created_on": map[string]interface{}{
+					"gte":       month + "||/M",
+					"lt":        month + "||+1M/M",
+					"format":    "yyyy-MM-dd",
+					"time_zone": "Europe/Istanbul",
+				},
+			},
+		},
+	}
+	queryJSON, err := json.Marshal(query)
+	if err != nil {
+		log.Fatalf("Sorgu JSON'a dönüştürülemedi: %s", err)
+	}
+
+	res, err := es.Count(
+		es.Count.WithIndex(indexName),
+		es.Count.WithBody(bytes.NewReader(queryJSON)),
+		es.Count.WithContext(context.Background()),
+	)
+	if err != nil {
+		log.Fatalf("Belge sayısı getirilemedi: %s", err)
+	}
+	defer res.Body.Close()
+
+	// Yanıtı kontrol et
+	if res.IsError() {
+		log.Fatalf("Belge sayısı getirme sırasında hata oluştu: %s", res.String())
+	}
+
+	// Yanıtı ayrıştır
+	var countResp CountResponse
+	if err := json.NewDecoder(res.Body).Decode(&countResp); err != nil {
+		log.Fatalf("Belge sayısı yanıtı ayrıştırılamadı: %s", err)
+	}
+
+	fmt.Printf("%s ayında oluşturulan belge sayısı: %d\n", month[:7], countResp.Count)
+}
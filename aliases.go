@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/SadikSunbul/Go-Elasticsearch/indexops"
+	"github.com/SadikSunbul/Go-Elasticsearch/q"
+	"github.com/elastic/go-elasticsearch/v8"
+)
+
+/*
+	Ürün Alias'ları:
+	Servisler "products" adına okur ve yazar, fiziksel indeks products-v3 gibi sürümlü bir addır
+	Fiziksel indeks adları products template'inin products-* desenine uyar; mapping, analyzer'lar ve
+	ingest pipeline'ı template'ten gelir
+
+	products                 -> products-v3 (yazma indeksi)
+	tenant-products-nike     -> products-v3 (sadece brand = Nike)
+	tenant-products-adidas   -> products-v3 (sadece brand = Adidas)
+
+	Her marka (kiracı) kendi alias'ı üzerinden sadece kendi ürünlerini görür
+	"go run . aliases list", "aliases swap products-v4", "aliases tenants products-v3 Nike Adidas"
+	ve "aliases remove products-v3 tenant-products-nike" komutlarıyla yönetilir
+	Kiracı alias'ları tenant- önekini taşır; products-<marka> fiziksel products-vN indeksleriyle,
+	products-* template deseniyle ve products* joker aramalarıyla çakışırdı
+*/
+
+// productsAlias, servislerin ürünleri okuduğu ve yazdığı alias'tır
+const productsAlias = "products"
+
+// tenantAliasPrefix, markaya özel filtreli alias'ların önekidir (örn: tenant-products-nike)
+const tenantAliasPrefix = "tenant-" + productsAlias + "-"
+
+// ProductsIndex, ürünlerin version'ıncı fiziksel indeksinin adıdır (örn: products-v1)
+func ProductsIndex(version int) string {
+	return fmt.Sprintf("%s-v%d", productsAlias, version)
+}
+
+// PointProductsAlias, products alias'ını ve kiracı alias'larını index'e tek bir atomik istekte taşır
+// ve index'i yazma indeksi yapar; kiracı alias'ları filtreleriyle birlikte taşınır
+// products hâlâ fiziksel bir indeks ise alias oluşturulamaz; önce yeni indekse reindex edilip silinmelidir
+func PointProductsAlias(client *elasticsearch.TypedClient, index string) (*indexops.Result, error) {
+	aliases, err := indexops.ListAliases(client, productsAlias)
+	if err != nil {
+		return nil, err
+	}
+	if len(aliases) == 0 {
+		exists, err := client.Indices.Exists(productsAlias).Do(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, fmt.Errorf("%s bir indeks, alias değil: belgeleri %s indeksine reindex edip %s indeksini silin", productsAlias, index, productsAlias)
+		}
+	}
+
+	// products alias'ının işaret ettiği indekslerdeki kiracı alias'ları da taşınır;
+	// taşınmazlarsa kiracılar eski indeksten bayat veri okumaya devam eder
+	current := make(map[string]bool, len(aliases))
+	for _, alias := range aliases {
+		current[alias.Index] = true
+	}
+	tenants, err := indexops.ListAliases(client, tenantAliasPrefix+"*")
+	if err != nil {
+		return nil, err
+	}
+
+	isWriteIndex := true
+	moved := []indexops.Alias{{Name: productsAlias, Index: index, IsWriteIndex: &isWriteIndex}}
+	seen := map[string]bool{}
+	for _, tenant := range tenants {
+		if !current[tenant.Index] || seen[tenant.Name] {
+			continue
+		}
+		seen[tenant.Name] = true
+		tenant.Index = index
+		moved = append(moved, tenant)
+	}
+	return indexops.SwapAlias(client, moved...)
+}
+
+// TenantAlias, markanın sadece kendi ürünlerini gördüğü filtreli alias'tır (örn: tenant-products-nike)
+// Belgeler marka ile route edilmediği için routing kullanılmaz; arama tüm shard'larda filtreyle yapılır
+func TenantAlias(index, brand string) indexops.Alias {
+	brand = titleBrand(brand)
+	// term sorgusu hata dönmez
+	filter, _ := q.Term("brand.keyword", brand).Build()
+	return indexops.Alias{
+		Name:   tenantAliasPrefix + strings.ToLower(strings.ReplaceAll(brand, " ", "-")),
+		Index:  index,
+		Filter: filter,
+	}
+}
+
+// titleBrand, markayı products-pipeline'ının yazdığı biçime getirir (" nIKE " -> "Nike");
+// brand.keyword normalizer'sız olduğu için filtre başka bir yazımla hiçbir belgeyle eşleşmez
+func titleBrand(brand string) string {
+	var words []string
+	for _, word := range strings.Split(strings.ToLower(strings.TrimSpace(brand)), " ") {
+		if word == "" {
+			continue
+		}
+		first, size := utf8.DecodeRuneInString(word)
+		words = append(words, string(unicode.ToUpper(first))+word[size:])
+	}
+	return strings.Join(words, " ")
+}
+
+// AddTenantAliases, her marka için filtreli alias'ı tek istekte ekler
+func AddTenantAliases(client *elasticsearch.TypedClient, index string, brands ...string) (*indexops.Result, error) {
+	aliases := make([]indexops.Alias, 0, len(brands))
+	for _, brand := range brands {
+		aliases = append(aliases, TenantAlias(index, brand))
+	}
+	return indexops.AddAliases(client, aliases...)
+}
+
+// runAliasesCommand, "aliases list|swap|tenants|remove" komutlarını çalıştırır
+func runAliasesCommand(client *elasticsearch.TypedClient, args []string) error {
+	usage := fmt.Errorf("kullanım: aliases list | swap <indeks> | tenants <indeks> <marka>... | remove <indeks> <alias>")
+	if len(args) == 0 {
+		return usage
+	}
+
+	var (
+		result *indexops.Result
+		err    error
+	)
+	switch {
+	case args[0] == "list" && len(args) == 1:
+		aliases, err := indexops.ListAliases(client, productsAlias+","+tenantAliasPrefix+"*")
+		if err != nil {
+			return err
+		}
+		for _, alias := range aliases {
+			fmt.Println(alias)
+		}
+		return nil
+	case args[0] == "swap" && len(args) == 2:
+		result, err = PointProductsAlias(client, args[1])
+	case args[0] == "tenants" && len(args) >= 3:
+		result, err = AddTenantAliases(client, args[1], args[2:]...)
+	case args[0] == "remove" && len(args) == 3:
+		result, err = indexops.RemoveAlias(client, args[1], args[2])
+	default:
+		return usage
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Println(result)
+	return nil
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTenantAlias(t *testing.T) {
+	tests := []struct {
+		brand      string
+		wantName   string
+		wantFilter string
+	}{
+		// Filtre products-pipeline'ının yazdığı biçimle eşleşir
+		{"Nike", "tenant-products-nike", `{"term":{"brand.keyword":{"value":"Nike"}}}`},
+		{" nIKE ", "tenant-products-nike", `{"term":{"brand.keyword":{"value":"Nike"}}}`},
+		{"new  BALANCE", "tenant-products-new-balance", `{"term":{"brand.keyword":{"value":"New Balance"}}}`},
+	}
+	for _, tt := range tests {
+		alias := TenantAlias("products-v3", tt.brand)
+		if alias.Name != tt.wantName {
+			t.Errorf("%q: alias %s, %s olmalı", tt.brand, alias.Name, tt.wantName)
+		}
+		filter, err := json.Marshal(alias.Filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(filter) != tt.wantFilter {
+			t.Errorf("%q: filtre %s, %s olmalı", tt.brand, filter, tt.wantFilter)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+)
+
+/*
+	Türkçe Analyzer'lar:
+	Standart analyzer "I" harfini "i"ye küçültür, Türkçede ise "I" -> "ı" ve "İ" -> "i" olmalıdır
+	Bu yüzden "AYAKKABI" ve "Ayakkabı" farklı terimlere dönüşür
+	turkish_text:   Türkçe küçültme + stop words + kök bulma (stemmer), tam metin arama için
+	turkish_folded: Türkçe küçültme + ascii folding, "ayakkabi" yazan kullanıcının "Ayakkabı"yı bulması için
+	turkish_plain:  sadece Türkçe küçültme, yazım önerileri (suggest) için okunabilir terimler üretir
+	turkish_keyword (normalizer): keyword alanlarda büyük/küçük harf ve aksan duyarsız eşleşme için
+	                              (Product'ta name.norm ve category.norm, `es:"text,norm=turkish_keyword"`)
+*/
+
+const (
+	TurkishTextAnalyzer      = "turkish_text"
+	TurkishFoldedAnalyzer    = "turkish_folded"
+	TurkishPlainAnalyzer     = "turkish_plain"
+	TurkishKeywordNormalizer = "turkish_keyword"
+
+	// FoldedSubField, ascii folding uygulanmış text alt alanının adıdır (örn: category.folded)
+	FoldedSubField = "folded"
+	// SuggestSubField, yazım önerileri için kullanılan text alt alanının adıdır (örn: category.suggest)
+	SuggestSubField = "suggest"
+	// NormalizedSubField, turkish_keyword normalizer'ı uygulanmış keyword alt alanının adıdır (örn: category.norm)
+	NormalizedSubField = "norm"
+)
+
+// TurkishAnalysis, Türkçe metinler için özel analyzer, filter ve normalizer tanımlarını döner
+func TurkishAnalysis() *types.IndexSettingsAnalysis {
+	turkish := "turkish"
+
+	return &types.IndexSettingsAnalysis{
+		Filter: map[string]types.TokenFilter{
+			"turkish_lowercase": &types.LowercaseTokenFilter{
+				Type:     "lowercase",
+				Language: &turkish,
+			},
+			"turkish_stop": &types.StopTokenFilter{
+				Type:      "stop",
+				Stopwords: []string{"_turkish_"},
+			},
+			"turkish_stemmer": &types.StemmerTokenFilter{
+				Type:     "stemmer",
+				Language: &turkish,
+			},
+			"turkish_asciifolding": &types.AsciiFoldingTokenFilter{
+				Type: "asciifolding",
+			},
+		},
+		Analyzer: map[string]types.Analyzer{
+			TurkishTextAnalyzer: &types.CustomAnalyzer{
+				Type:      "custom",
+				Tokenizer: "standard",
+				Filter:    []string{"apostrophe", "turkish_lowercase", "turkish_stop", "turkish_stemmer"},
+			},
+			TurkishFoldedAnalyzer: &types.CustomAnalyzer{
+				Type:      "custom",
+				Tokenizer: "standard",
+				Filter:    []string{"apostrophe", "turkish_lowercase", "turkish_asciifolding"},
+			},
+			TurkishPlainAnalyzer: &types.CustomAnalyzer{
+				Type:      "custom",
+				Tokenizer: "standard",
+				Filter:    []string{"apostrophe", "turkish_lowercase"},
+			},
+		},
+		Normalizer: map[string]types.Normalizer{
+			TurkishKeywordNormalizer: &types.CustomNormalizer{
+				Type:   "custom",
+				Filter: []string{"turkish_lowercase", "turkish_asciifolding"},
+			},
+		},
+	}
+}
+
+// ProductIndexSettings, products indeksinin ayarlarını (Türkçe analyzer'lar dahil) döner
+func ProductIndexSettings() *types.IndexSettings {
+	return &types.IndexSettings{
+		Analysis: TurkishAnalysis(),
+	}
+}
+
+// TurkishTextFields, Türkçe analyze edilmiş bir alan için sorgulanacak alanları döner
+// Ana alan kök bulma ile eşleşir, .folded alt alanı ise Türkçe karakter yazılmamış sorguları yakalar
+func TurkishTextFields(field string) []string {
+	return []string{field, field + "." + FoldedSubField}
+}
+
+// turkishTextQuery, alanın Türkçe alt alanlarını birlikte hedefleyen multi_match sorgusu üretir
+func turkishTextQuery(field, text string) map[string]interface{} {
+	return map[string]interface{}{
+		"multi_match": map[string]interface{}{
+			"query":  text,
+			"fields": TurkishTextFields(field),
+			"type":   "most_fields",
+		},
+	}
+}
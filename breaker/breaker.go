@@ -0,0 +1,318 @@
+/*
+Package breaker, Elasticsearch istekleri için işlem sınıfına göre zaman aşımı ve bir circuit breaker sağlar
+
+Örneklerin her yerde kullandığı context.Background() deadline içermediği için küme aşırı yüklendiğinde
+çağrılar süresiz bekleyebilir. Bu paketteki Transport deadline'ı olmayan her isteğe, isteğin sınıfına
+(search, get, index, bulk, admin) göre bir zaman aşımı ekler; çağıranın kendi deadline'ı varsa ona dokunmaz
+
+Breaker art arda başarısız olan isteklerden sonra açılır ve bekleme süresi (cooldown) boyunca istekleri
+kümeye göndermeden ErrOpen ile hemen reddeder. Süre dolunca yarı açık duruma geçer: tek bir deneme
+isteği gönderilir, başarılı olursa breaker kapanır, başarısız olursa tekrar açılır
+
+	client, err := elasticsearch.NewTypedClient(breaker.Configure(retry.Configure(elasticsearch.Config{
+		Addresses: []string{"http://localhost:9200"},
+	})))
+
+Breaker retry transport'unun dışında durur: tekrar denemelerin hepsi tek bir istek sayılır ve zaman aşımı
+tekrar denemeleri de kapsar. Configure her istemciye kendi breaker'ını verir; aynı kümeye bağlanan
+istemcilerin tek bir breaker'ı paylaşması isteniyorsa New ile oluşturulan breaker ConfigureWith ile verilir:
+
+	clusterBreaker := breaker.New(breaker.DefaultThreshold, breaker.DefaultCooldown)
+	cfg = breaker.ConfigureWith(retry.Configure(cfg), clusterBreaker)
+	fmt.Println(clusterBreaker.Snapshot())
+*/
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8"
+)
+
+// State, breaker'ın durumudur
+type State int
+
+const (
+	Closed   State = iota // istekler gönderilir, başarısızlıklar sayılır
+	Open                  // istekler gönderilmeden reddedilir
+	HalfOpen              // cooldown doldu, tek bir deneme isteği gönderilir
+)
+
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "kapalı"
+	case Open:
+		return "açık"
+	case HalfOpen:
+		return "yarı açık"
+	default:
+		return fmt.Sprintf("State(%d)", int(s))
+	}
+}
+
+// ErrOpen, breaker açıkken gönderilmeden reddedilen isteklerin hatasıdır; errors.Is ile kontrol edilir
+var ErrOpen = errors.New("circuit breaker açık, istek gönderilmedi")
+
+// Breaker, art arda başarısızlıklardan sonra istekleri bir süre reddeden circuit breaker'dır
+// Eşzamanlı kullanıma uygundur
+type Breaker struct {
+	threshold int           // breaker'ı açan art arda başarısızlık sayısı
+	cooldown  time.Duration // açık kaldığı süre
+
+	mu         sync.Mutex
+	state      State
+	generation uint64 // durum her değiştiğinde ve her yeni deneme isteğinde artar
+	failures   int    // art arda başarısızlık sayısı
+	openedAt   time.Time
+	probing    bool // yarı açıkken deneme isteği gönderildi mi
+	rejected   int64
+	lastError  error
+
+	// OnStateChange, durum her değiştiğinde çağrılır (örn: loglama); kilit tutulurken çağrılmaz
+	OnStateChange func(from, to State)
+}
+
+// Ticket, Allow'un izin verdiği isteği tanımlar; isteğin sonucu aynı Ticket ile Record'a bildirilir
+// Breaker'ın durumu istek sürerken değiştiyse (örn: breaker açıldıysa) sonuç yok sayılır
+type Ticket struct {
+	generation uint64
+}
+
+// Configure'un oluşturduğu breaker'ların ayarlarıdır
+const (
+	DefaultThreshold = 5
+	DefaultCooldown  = 30 * time.Second
+)
+
+// New, threshold art arda başarısızlıktan sonra açılan ve cooldown boyunca açık kalan bir breaker oluşturur
+func New(threshold int, cooldown time.Duration) *Breaker {
+	return &Breaker{threshold: threshold, cooldown: cooldown}
+}
+
+// State, breaker'ın şu anki durumunu döner; cooldown dolmuşsa yarı açık döner
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == Open && time.Since(b.openedAt) >= b.cooldown {
+		return HalfOpen
+	}
+	return b.state
+}
+
+// Allow, isteğin gönderilip gönderilemeyeceğini döner
+// Hata nil ise istek gönderilmeli ve sonucu dönen Ticket ile Record'a bildirilmelidir
+func (b *Breaker) Allow() (Ticket, error) {
+	b.mu.Lock()
+	from := b.state
+	var err error
+	switch b.state {
+	case Open:
+		remaining := b.cooldown - time.Since(b.openedAt)
+		if remaining > 0 {
+			b.rejected++
+			err = fmt.Errorf("%w (son hata: %v), %s sonra tekrar denenebilir", ErrOpen, b.lastError, remaining.Round(100*time.Millisecond))
+			break
+		}
+		b.setState(HalfOpen)
+		b.probing = true
+	case HalfOpen:
+		// Deneme isteğinin sonucu gelene kadar diğer istekler beklemeden reddedilir
+		if b.probing {
+			b.rejected++
+			err = fmt.Errorf("%w (yarı açık, deneme isteğinin sonucu bekleniyor)", ErrOpen)
+			break
+		}
+		// Bırakılan denemenin yerine yeni deneme: önceki denemenin Ticket'ı geçersiz olur
+		b.generation++
+		b.probing = true
+	}
+	ticket := Ticket{generation: b.generation}
+	to := b.state
+	b.mu.Unlock()
+
+	b.notify(from, to)
+	if err != nil {
+		return Ticket{}, err
+	}
+	return ticket, nil
+}
+
+// Record, Allow'un izin verdiği isteğin sonucunu bildirir; err nil değilse istek başarısız sayılır
+// İstek sürerken breaker'ın durumu değiştiyse sonuç yok sayılır: kapalıyken başlamış ve geç biten bir istek
+// açık breaker'ı cooldown dolmadan kapatamaz, yarı açıkken süren deneme isteğinin yerine de geçemez
+func (b *Breaker) Record(ticket Ticket, err error) {
+	b.mu.Lock()
+	if ticket.generation != b.generation {
+		b.mu.Unlock()
+		return
+	}
+	from := b.state
+	b.probing = false
+	if err == nil {
+		b.failures = 0
+		b.setState(Closed)
+	} else {
+		b.failures++
+		b.lastError = err
+		if b.state == HalfOpen || b.failures >= b.threshold {
+			b.setState(Open)
+			b.openedAt = time.Now()
+		}
+	}
+	to := b.state
+	b.mu.Unlock()
+
+	b.notify(from, to)
+}
+
+// release, sonucu bilinmeyen isteğin iznini durumu değiştirmeden geri verir
+// Sadece yarı açıkken süren deneme isteği için etkilidir: bir sonraki istek yeni deneme isteği olur
+func (b *Breaker) release(ticket Ticket) {
+	b.mu.Lock()
+	if ticket.generation == b.generation && b.state == HalfOpen {
+		b.probing = false
+	}
+	b.mu.Unlock()
+}
+
+// setState, durumu değiştirir ve önceki durumda verilen Ticket'ları geçersiz kılar; kilit tutulurken çağrılır
+func (b *Breaker) setState(state State) {
+	if b.state != state {
+		b.state = state
+		b.generation++
+	}
+}
+
+func (b *Breaker) notify(from, to State) {
+	if from != to && b.OnStateChange != nil {
+		b.OnStateChange(from, to)
+	}
+}
+
+// Snapshot, breaker'ın anlık durumudur
+type Snapshot struct {
+	State     State
+	Failures  int       // art arda başarısızlık sayısı
+	Rejected  int64     // açıkken gönderilmeden reddedilen istek sayısı
+	OpenedAt  time.Time // son açıldığı zaman; hiç açılmadıysa sıfır
+	LastError error
+}
+
+// Snapshot, breaker'ın anlık durumunu döner
+func (b *Breaker) Snapshot() Snapshot {
+	state := b.State()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return Snapshot{
+		State:     state,
+		Failures:  b.failures,
+		Rejected:  b.rejected,
+		OpenedAt:  b.openedAt,
+		LastError: b.lastError,
+	}
+}
+
+func (s Snapshot) String() string {
+	out := fmt.Sprintf("durum: %s, art arda başarısızlık: %d, reddedilen: %d", s.State, s.Failures, s.Rejected)
+	if s.State != Closed && s.LastError != nil {
+		out += fmt.Sprintf(", son hata: %v", s.LastError)
+	}
+	return out
+}
+
+// Transport, isteklere zaman aşımı ekleyen ve breaker'dan geçiren http.RoundTripper'dır
+type Transport struct {
+	Next     http.RoundTripper // nil ise http.DefaultTransport
+	Breaker  *Breaker          // nil ise breaker kullanılmaz, sadece zaman aşımı uygulanır
+	Timeouts Timeouts          // sınıfı listede olmayan isteklere zaman aşımı eklenmez
+}
+
+// Configure, cfg'yi bu istemciye ait yeni bir breaker ve DefaultTimeouts ile ayarlar
+// retry.Configure ile birlikte kullanılırken breaker dışta kalacak şekilde en son çağrılmalıdır
+func Configure(cfg elasticsearch.Config) elasticsearch.Config {
+	return ConfigureWith(cfg, New(DefaultThreshold, DefaultCooldown))
+}
+
+// ConfigureWith, cfg'yi verilen breaker ve DefaultTimeouts ile ayarlar
+// Aynı breaker birden fazla istemciye verilirse başarısızlıkları birlikte sayılır
+func ConfigureWith(cfg elasticsearch.Config, b *Breaker) elasticsearch.Config {
+	cfg.Transport = &Transport{Next: cfg.Transport, Breaker: b, Timeouts: DefaultTimeouts}
+	return cfg
+}
+
+// RoundTrip, isteği sınıfının zaman aşımıyla ve breaker açık değilse gönderir
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	var ticket Ticket
+	if t.Breaker != nil {
+		var err error
+		if ticket, err = t.Breaker.Allow(); err != nil {
+			if req.Body != nil {
+				req.Body.Close()
+			}
+			return nil, err
+		}
+	}
+
+	cancel := context.CancelFunc(func() {})
+	if _, ok := req.Context().Deadline(); !ok {
+		if timeout, ok := t.Timeouts[Classify(req)]; ok && timeout > 0 {
+			var ctx context.Context
+			ctx, cancel = context.WithTimeout(req.Context(), timeout)
+			req = req.WithContext(ctx)
+		}
+	}
+
+	res, err := next.RoundTrip(req)
+	if t.Breaker != nil {
+		if errors.Is(err, context.Canceled) {
+			// Çağıranın iptal ettiği istek kümenin durumu hakkında bilgi vermez
+			t.Breaker.release(ticket)
+		} else {
+			t.Breaker.Record(ticket, failure(req, res, err))
+		}
+	}
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	// Yanıt gövdesi okunurken de deadline geçerli kalsın diye context gövde kapatılınca iptal edilir
+	res.Body = &cancelOnClose{ReadCloser: res.Body, cancel: cancel}
+	return res, nil
+}
+
+// failure, isteğin breaker açısından başarısız sayılıp sayılmayacağını döner
+// Sadece kümenin yük altında ya da erişilemez olduğunu gösteren hatalar sayılır (zaman aşımı dahil);
+// 404 ya da mapping hatası gibi isteğin kendisinden kaynaklanan hatalar sayılmaz
+func failure(req *http.Request, res *http.Response, err error) error {
+	if err != nil {
+		return err
+	}
+	switch res.StatusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return fmt.Errorf("HTTP %d: %s %s", res.StatusCode, req.Method, req.URL.Path)
+	}
+	return nil
+}
+
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
@@ -0,0 +1,364 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var errCluster = errors.New("HTTP 503")
+
+// call, izin verilen tek bir isteğin sonucunu breaker'a bildirir
+func call(t *testing.T, b *Breaker, result error) {
+	t.Helper()
+	ticket, err := b.Allow()
+	if err != nil {
+		t.Fatalf("istek reddedildi: %v", err)
+	}
+	b.Record(ticket, result)
+}
+
+func TestBreakerOpensAfterThreshold(t *testing.T) {
+	b := New(3, time.Hour)
+	for i := 0; i < 2; i++ {
+		call(t, b, errCluster)
+	}
+	if b.State() != Closed {
+		t.Fatalf("eşik dolmadan durum %s", b.State())
+	}
+
+	// Araya giren başarılı istek art arda başarısızlık sayısını sıfırlar
+	call(t, b, nil)
+	for i := 0; i < 2; i++ {
+		call(t, b, errCluster)
+	}
+	if b.State() != Closed {
+		t.Fatalf("başarılı istekten sonra sayaç sıfırlanmadı, durum %s", b.State())
+	}
+
+	call(t, b, errCluster)
+	if b.State() != Open {
+		t.Fatalf("3 art arda başarısızlıktan sonra durum %s, açık olmalı", b.State())
+	}
+
+	_, err := b.Allow()
+	if !errors.Is(err, ErrOpen) {
+		t.Fatalf("açık breaker'da Allow = %v, ErrOpen olmalı", err)
+	}
+	if snapshot := b.Snapshot(); snapshot.Rejected != 1 || snapshot.Failures != 3 {
+		t.Errorf("Snapshot = %+v", snapshot)
+	}
+}
+
+// openBreaker, cooldown'u dolmuş açık bir breaker döner
+func openBreaker(t *testing.T) *Breaker {
+	t.Helper()
+	b := New(1, 10*time.Millisecond)
+	call(t, b, errCluster)
+	if b.State() != Open {
+		t.Fatalf("durum %s, açık olmalı", b.State())
+	}
+	time.Sleep(20 * time.Millisecond)
+	if b.State() != HalfOpen {
+		t.Fatalf("cooldown dolduktan sonra durum %s, yarı açık olmalı", b.State())
+	}
+	return b
+}
+
+func TestBreakerHalfOpenProbe(t *testing.T) {
+	t.Run("başarılı deneme breaker'ı kapatır", func(t *testing.T) {
+		b := openBreaker(t)
+		probe, err := b.Allow()
+		if err != nil {
+			t.Fatalf("deneme isteği reddedildi: %v", err)
+		}
+		// Deneme isteğinin sonucu gelene kadar diğer istekler reddedilir
+		if _, err := b.Allow(); !errors.Is(err, ErrOpen) {
+			t.Fatalf("ikinci istek %v, ErrOpen olmalı", err)
+		}
+		b.Record(probe, nil)
+		if b.State() != Closed {
+			t.Fatalf("durum %s, kapalı olmalı", b.State())
+		}
+		if _, err := b.Allow(); err != nil {
+			t.Fatalf("kapalı breaker isteği reddetti: %v", err)
+		}
+	})
+
+	t.Run("başarısız deneme breaker'ı tekrar açar", func(t *testing.T) {
+		b := openBreaker(t)
+		call(t, b, errCluster)
+		if b.State() != Open {
+			t.Fatalf("durum %s, açık olmalı", b.State())
+		}
+		if _, err := b.Allow(); !errors.Is(err, ErrOpen) {
+			t.Fatalf("tekrar açılan breaker'da Allow = %v, ErrOpen olmalı", err)
+		}
+	})
+
+	t.Run("release sonraki isteği deneme isteği yapar", func(t *testing.T) {
+		b := openBreaker(t)
+		released, _ := b.Allow()
+		b.release(released)
+		if b.State() != HalfOpen {
+			t.Fatalf("release durumu değiştirdi: %s", b.State())
+		}
+		probe, err := b.Allow()
+		if err != nil {
+			t.Fatalf("release'den sonraki istek reddedildi: %v", err)
+		}
+		if _, err := b.Allow(); !errors.Is(err, ErrOpen) {
+			t.Fatalf("yeni deneme isteği sürerken Allow = %v, ErrOpen olmalı", err)
+		}
+
+		// Bırakılan denemenin Ticket'ı yeni denemenin yerine geçemez
+		b.Record(released, nil)
+		b.release(released)
+		if b.State() != HalfOpen {
+			t.Fatalf("eski deneme durumu değiştirdi: %s", b.State())
+		}
+		if _, err := b.Allow(); !errors.Is(err, ErrOpen) {
+			t.Fatalf("eski denemeden sonra Allow = %v, yeni deneme sürerken ErrOpen olmalı", err)
+		}
+		b.Record(probe, nil)
+		if b.State() != Closed {
+			t.Fatalf("durum %s, kapalı olmalı", b.State())
+		}
+	})
+}
+
+func TestBreakerIgnoresStaleResults(t *testing.T) {
+	t.Run("açıkken gelen geç başarı breaker'ı kapatmaz", func(t *testing.T) {
+		b := New(1, time.Hour)
+		late, _ := b.Allow()
+		call(t, b, errCluster)
+		b.Record(late, nil)
+		if b.State() != Open {
+			t.Fatalf("kapalıyken başlamış isteğin başarısı cooldown dolmadan breaker'ı kapattı: %s", b.State())
+		}
+	})
+
+	t.Run("yarı açıkken gelen geç sonuçlar denemeyi etkilemez", func(t *testing.T) {
+		b := New(1, 10*time.Millisecond)
+		lateFailure, _ := b.Allow()
+		lateSuccess, _ := b.Allow()
+		b.Record(lateFailure, errCluster)
+		time.Sleep(20 * time.Millisecond)
+
+		probe, err := b.Allow()
+		if err != nil {
+			t.Fatalf("deneme isteği reddedildi: %v", err)
+		}
+		// Kapalıyken başlamış isteklerin sonuçları deneme sürerken gelir
+		b.Record(lateSuccess, errCluster)
+		if b.State() != HalfOpen {
+			t.Fatalf("geç başarısızlık breaker'ı tekrar açtı: %s", b.State())
+		}
+		b.Record(lateSuccess, nil)
+		if b.State() != HalfOpen {
+			t.Fatalf("geç başarı breaker'ı kapattı: %s", b.State())
+		}
+		if _, err := b.Allow(); !errors.Is(err, ErrOpen) {
+			t.Fatalf("geç sonuçlardan sonra ikinci deneme gönderildi: %v", err)
+		}
+
+		b.Record(probe, nil)
+		if b.State() != Closed {
+			t.Fatalf("durum %s, kapalı olmalı", b.State())
+		}
+	})
+
+	t.Run("iptal edilen normal istek denemeyi bırakmaz", func(t *testing.T) {
+		b := New(1, 10*time.Millisecond)
+		canceled, _ := b.Allow()
+		call(t, b, errCluster)
+		time.Sleep(20 * time.Millisecond)
+
+		if _, err := b.Allow(); err != nil {
+			t.Fatalf("deneme isteği reddedildi: %v", err)
+		}
+		b.release(canceled)
+		if _, err := b.Allow(); !errors.Is(err, ErrOpen) {
+			t.Fatalf("iptal edilen normal istek ikinci bir denemeye izin verdi: %v", err)
+		}
+	})
+}
+
+func TestBreakerOnStateChange(t *testing.T) {
+	b := New(1, 10*time.Millisecond)
+	var changes []string
+	b.OnStateChange = func(from, to State) {
+		changes = append(changes, from.String()+"->"+to.String())
+	}
+
+	call(t, b, errCluster)
+	time.Sleep(20 * time.Millisecond)
+	call(t, b, nil)
+
+	want := []string{"kapalı->açık", "açık->yarı açık", "yarı açık->kapalı"}
+	if len(changes) != len(want) {
+		t.Fatalf("geçişler %v, %v olmalı", changes, want)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("%d. geçiş %q, %q olmalı", i+1, changes[i], want[i])
+		}
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		method, path string
+		want         Class
+	}{
+		{http.MethodPost, "/products/_search", Search},
+		{http.MethodPost, "/_msearch", Search},
+		{http.MethodGet, "/products/_count", Search},
+		{http.MethodGet, "/products/_doc/1", Get},
+		{http.MethodHead, "/products/_doc/1", Get},
+		{http.MethodGet, "/products/_source/1", Get},
+		{http.MethodPost, "/_mget", Get},
+		{http.MethodPut, "/products/_doc/1", Index},
+		{http.MethodPost, "/products/_doc", Index},
+		{http.MethodDelete, "/products/_doc/1", Index},
+		{http.MethodPost, "/products/_update/1", Index},
+		{http.MethodPost, "/_bulk", Bulk},
+		{http.MethodPost, "/products/_update_by_query", Bulk},
+		{http.MethodPost, "/_reindex", Bulk},
+		{http.MethodPost, "/products/_forcemerge", Bulk},
+		{http.MethodPost, "/_snapshot/backups/s1/_restore?wait_for_completion=true", Bulk},
+		{http.MethodPost, "/_snapshot/backups/s1/_restore", Admin},
+		{http.MethodPut, "/products", Admin},
+		{http.MethodHead, "/products", Admin},
+		{http.MethodGet, "/_cat/indices", Admin},
+		{http.MethodPut, "/_index_template/products", Admin},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		if got := Classify(req); got != tt.want {
+			t.Errorf("Classify(%s %s) = %s, %s olmalı", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestTransport(t *testing.T) {
+	var (
+		status int32 = http.StatusServiceUnavailable
+		calls  int32
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if r.URL.Path == "/products/_search" {
+			// Aramalar yavaş: zaman aşımına uğramalı
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+			return
+		}
+		w.WriteHeader(int(atomic.LoadInt32(&status)))
+	}))
+	defer server.Close()
+
+	b := New(2, 50*time.Millisecond)
+	transport := &Transport{Breaker: b, Timeouts: Timeouts{Search: 20 * time.Millisecond, Get: time.Second}}
+	get := func(path string) (*http.Response, error) {
+		req, _ := http.NewRequest(http.MethodGet, server.URL+path, nil)
+		res, err := transport.RoundTrip(req)
+		if err == nil {
+			io.Copy(io.Discard, res.Body)
+			res.Body.Close()
+		}
+		return res, err
+	}
+
+	// 404 gibi isteğin kendisinden kaynaklanan hatalar breaker'ı açmaz
+	atomic.StoreInt32(&status, http.StatusNotFound)
+	for i := 0; i < 3; i++ {
+		get("/products/_doc/1")
+	}
+	if b.State() != Closed {
+		t.Fatalf("404'lerden sonra durum %s, kapalı olmalı", b.State())
+	}
+
+	// Zaman aşımı başarısızlık sayılır ve iki tanesi breaker'ı açar
+	for i := 0; i < 2; i++ {
+		if _, err := get("/products/_search"); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("yavaş arama hatası %v, zaman aşımı olmalı", err)
+		}
+	}
+	if b.State() != Open {
+		t.Fatalf("zaman aşımlarından sonra durum %s, açık olmalı", b.State())
+	}
+
+	// Açıkken istek sunucuya gitmeden reddedilir
+	before := atomic.LoadInt32(&calls)
+	if _, err := get("/products/_doc/1"); !errors.Is(err, ErrOpen) {
+		t.Fatalf("açık breaker'da hata %v, ErrOpen olmalı", err)
+	}
+	if atomic.LoadInt32(&calls) != before {
+		t.Error("açık breaker isteği sunucuya gönderdi")
+	}
+
+	// Cooldown'dan sonra başarılı deneme isteği breaker'ı kapatır
+	time.Sleep(60 * time.Millisecond)
+	atomic.StoreInt32(&status, http.StatusOK)
+	if _, err := get("/products/_doc/1"); err != nil {
+		t.Fatalf("deneme isteği başarısız: %v", err)
+	}
+	if b.State() != Closed {
+		t.Fatalf("başarılı denemeden sonra durum %s, kapalı olmalı", b.State())
+	}
+}
+
+func TestTransportKeepsCallerDeadline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	// Çağıranın deadline'ı sınıfın zaman aşımından uzunsa istek zaman aşımına uğramamalı
+	transport := &Transport{Timeouts: Timeouts{Admin: 10 * time.Millisecond}}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPut, server.URL+"/products", nil)
+	res, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("çağıranın deadline'ı yerine sınıfın zaman aşımı uygulandı: %v", err)
+	}
+	res.Body.Close()
+}
+
+func TestTransportCanceledRequestReleasesProbe(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	b := openBreaker(t)
+	transport := &Transport{Breaker: b}
+
+	// Çağıranın iptal ettiği deneme isteği breaker'ı ne kapatır ne de tekrar açar
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/products/_doc/1", nil)
+	if _, err := transport.RoundTrip(req); !errors.Is(err, context.Canceled) {
+		t.Fatalf("hata %v, context.Canceled olmalı", err)
+	}
+	if b.State() != HalfOpen {
+		t.Fatalf("iptal edilen denemeden sonra durum %s, yarı açık olmalı", b.State())
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, server.URL+"/products/_doc/1", nil)
+	res, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("sonraki deneme isteği reddedildi: %v", err)
+	}
+	res.Body.Close()
+	if b.State() != Closed {
+		t.Fatalf("durum %s, kapalı olmalı", b.State())
+	}
+}
@@ -0,0 +1,85 @@
+package breaker
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Class, isteğin işlem sınıfıdır; her sınıfın kendi zaman aşımı vardır
+type Class string
+
+const (
+	Search Class = "search" // _search, _msearch, _count gibi okuma sorguları
+	Get    Class = "get"    // tek belge okuma (GET /my_index/_doc/1, _mget)
+	Index  Class = "index"  // tek belge yazma, güncelleme ve silme
+	Bulk   Class = "bulk"   // _bulk, _update_by_query, _reindex gibi çok belgeye dokunan ya da uzun süren işlemler
+	Admin  Class = "admin"  // indeks, template, alias, snapshot, küme yönetimi
+)
+
+// Timeouts, işlem sınıflarının zaman aşımlarıdır
+type Timeouts map[Class]time.Duration
+
+// DefaultTimeouts, Configure ile oluşturulan istemcilerin kullandığı zaman aşımlarıdır
+// Süreler tekrar denemeleri de kapsar: retry transport'u bu sürenin içinde bekleyip tekrar dener
+var DefaultTimeouts = Timeouts{
+	Search: 15 * time.Second,
+	Get:    5 * time.Second,
+	Index:  10 * time.Second,
+	Bulk:   2 * time.Minute,
+	Admin:  1 * time.Minute,
+}
+
+// WithTimeout, ctx'e işlem sınıfının varsayılan zaman aşımını ekler
+// Transport deadline'ı olmayan isteklere bunu kendisi uygular; çağıran taraf sadece birden fazla isteği
+// tek bir süreyle sınırlamak istediğinde kullanır (örn: bir aramanın ardından yapılan _mget)
+func WithTimeout(ctx context.Context, class Class) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, DefaultTimeouts[class])
+}
+
+// bulkEndpoints, tek istekte çok sayıda belgeye dokunan ya da tamamlanması uzun sürebilen uç noktalardır
+var bulkEndpoints = []string{"_bulk", "_update_by_query", "_delete_by_query", "_reindex", "_forcemerge"}
+
+// searchEndpoints, belge döndüren ya da sayan okuma uç noktalarıdır
+var searchEndpoints = []string{"_search", "_msearch", "_count", "_explain", "_field_caps", "_validate", "_termvectors", "_mtermvectors", "_pit"}
+
+// Classify, isteğin işlem sınıfını yol ve metoda göre belirler
+func Classify(req *http.Request) Class {
+	segments := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+
+	// Tamamlanmasını bekleyen snapshot geri yüklemesi indeks boyutuyla orantılı sürer
+	if segments[len(segments)-1] == "_restore" && req.URL.Query().Get("wait_for_completion") == "true" {
+		return Bulk
+	}
+	for _, segment := range segments {
+		for _, endpoint := range bulkEndpoints {
+			if segment == endpoint {
+				return Bulk
+			}
+		}
+	}
+	for _, segment := range segments {
+		for _, endpoint := range searchEndpoints {
+			if segment == endpoint {
+				return Search
+			}
+		}
+	}
+
+	for i, segment := range segments {
+		switch segment {
+		case "_mget":
+			return Get
+		case "_doc", "_source", "_create", "_update":
+			// /my_index/_doc (ID'siz POST) ya da /my_index/_doc/1
+			if req.Method == http.MethodGet || req.Method == http.MethodHead {
+				if i+1 < len(segments) {
+					return Get
+				}
+			}
+			return Index
+		}
+	}
+	return Admin
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/SadikSunbul/Go-Elasticsearch/q"
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/core/search"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+)
+
+/*
+	Composite Aggregation ile Sayfalı Gruplama:
+	terms aggregation sadece en çok belgeye sahip ilk N grubu döner; raporlarda ise tüm
+	marka × kategori kombinasyonları gerekir. composite aggregation grupları anahtara göre
+	sıralı olarak sayfa sayfa döner, her sayfanın after_key'i bir sonraki sayfanın başlangıcıdır
+	Bucket anahtarları json tag'leri kaynak adlarıyla eşleşen bir struct'a (K) çözülür
+*/
+
+// defaultCompositePageSize, composite aggregation'ın sayfa başına döndüreceği bucket sayısıdır
+const defaultCompositePageSize = 500
+
+// CompositeSource, composite aggregation'daki tek bir gruplama kaynağıdır (terms)
+type CompositeSource struct {
+	Name          string // bucket anahtarındaki ad, K struct'ındaki json tag'i ile aynı olmalıdır
+	Field         string // gruplanacak keyword ya da sayısal alan
+	MissingBucket bool   // alanı olmayan belgeler için boş (null) anahtarlı bucket oluşturulsun mu
+}
+
+// CompositeBucket, tek bir grup ve belge sayısıdır
+type CompositeBucket[K any] struct {
+	Key      K
+	Values   map[string]types.FieldValue // kaynak adına göre ham anahtar değerleri
+	DocCount int64
+}
+
+// CompositePager, composite aggregation'ı after_key ile sayfa sayfa dolaşır
+type CompositePager[K any] struct {
+	client   *elasticsearch.TypedClient
+	index    string
+	query    q.Builder
+	sources  []CompositeSource
+	pageSize int
+
+	after types.CompositeAggregateKey
+	done  bool
+}
+
+// NewCompositePager, indeksteki belgeleri verilen kaynaklara göre gruplayan bir pager oluşturur
+// query nil ise tüm belgeler gruplanır, pageSize 0 ise 500 kullanılır
+func NewCompositePager[K any](client *elasticsearch.TypedClient, index string, query q.Builder, pageSize int, sources ...CompositeSource) *CompositePager[K] {
+	if pageSize <= 0 {
+		pageSize = defaultCompositePageSize
+	}
+	return &CompositePager[K]{
+		client:   client,
+		index:    index,
+		query:    query,
+		sources:  sources,
+		pageSize: pageSize,
+	}
+}
+
+// Done, tüm sayfalar okunduysa true döner
+func (p *CompositePager[K]) Done() bool {
+	return p.done
+}
+
+// Next, bir sonraki sayfanın bucket'larını döner; sayfalar bittiğinde nil döner
+func (p *CompositePager[K]) Next() ([]CompositeBucket[K], error) {
+	ctx := context.Background()
+
+	if p.done {
+		return nil, nil
+	}
+	if len(p.sources) == 0 {
+		return nil, fmt.Errorf("composite aggregation için en az bir kaynak gereklidir")
+	}
+
+	sources := make([]map[string]types.CompositeAggregationSource, len(p.sources))
+	for i, source := range p.sources {
+		field := source.Field
+		terms := &types.CompositeTermsAggregation{Field: &field}
+		if source.MissingBucket {
+			missingBucket := true
+			terms.MissingBucket = &missingBucket
+		}
+		sources[i] = map[string]types.CompositeAggregationSource{
+			source.Name: {Terms: terms},
+		}
+	}
+
+	size := 0
+	request := &search.Request{
+		Size: &size,
+		Aggregations: map[string]types.Aggregations{
+			"groups": {
+				Composite: &types.CompositeAggregation{
+					Sources: sources,
+					Size:    &p.pageSize,
+					After:   p.after,
+				},
+			},
+		},
+	}
+	if p.query != nil {
+		query, err := p.query.Build()
+		if err != nil {
+			return nil, err
+		}
+		request.Query = query
+	}
+
+	res, err := p.client.Search().Index(p.index).Request(request).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	aggregate, ok := res.Aggregations["groups"].(*types.CompositeAggregate)
+	if !ok {
+		return nil, fmt.Errorf("beklenmeyen aggregation tipi: %T", res.Aggregations["groups"])
+	}
+	buckets, ok := aggregate.Buckets.([]types.CompositeBucket)
+	if !ok {
+		return nil, fmt.Errorf("beklenmeyen bucket tipi: %T", aggregate.Buckets)
+	}
+
+	// after_key yoksa ya da sayfa boşsa gruplar bitmiştir
+	if len(buckets) == 0 || len(aggregate.AfterKey) == 0 {
+		p.done = true
+	}
+	p.after = aggregate.AfterKey
+
+	result := make([]CompositeBucket[K], 0, len(buckets))
+	for _, bucket := range buckets {
+		key, err := decodeCompositeKey[K](bucket.Key)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, CompositeBucket[K]{
+			Key:      key,
+			Values:   bucket.Key,
+			DocCount: bucket.DocCount,
+		})
+	}
+	return result, nil
+}
+
+// ForEach, tüm sayfalardaki bucket'lar için fn'i sırayla çağırır; fn hata dönerse durur
+func (p *CompositePager[K]) ForEach(fn func(CompositeBucket[K]) error) error {
+	for !p.done {
+		buckets, err := p.Next()
+		if err != nil {
+			return err
+		}
+		for _, bucket := range buckets {
+			if err := fn(bucket); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// WriteCSV, kalan tüm grupları kaynak sırasıyla sütunlara ve son sütunda doc_count olacak şekilde CSV'ye yazar
+// Gruplar bellekte biriktirilmez, her sayfa okunduğu gibi yazılır
+func (p *CompositePager[K]) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+
+	header := make([]string, 0, len(p.sources)+1)
+	for _, source := range p.sources {
+		header = append(header, source.Name)
+	}
+	header = append(header, "doc_count")
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	err := p.ForEach(func(bucket CompositeBucket[K]) error {
+		record := make([]string, 0, len(p.sources)+1)
+		for _, source := range p.sources {
+			record = append(record, compositeValueString(bucket.Values[source.Name]))
+		}
+		record = append(record, strconv.FormatInt(bucket.DocCount, 10))
+		return writer.Write(record)
+	})
+	if err != nil {
+		return err
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// decodeCompositeKey, bucket anahtarını JSON üzerinden K tipine çözer
+func decodeCompositeKey[K any](key types.CompositeAggregateKey) (K, error) {
+	var typed K
+	data, err := json.Marshal(key)
+	if err != nil {
+		return typed, err
+	}
+	if err := json.Unmarshal(data, &typed); err != nil {
+		return typed, fmt.Errorf("composite anahtarı %s çözülemedi: %w", data, err)
+	}
+	return typed, nil
+}
+
+// compositeValueString, bucket anahtar değerini CSV hücresine çevirir; eksik değer boş hücre olur
+func compositeValueString(value types.FieldValue) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// BrandCategoryKey, marka × kategori gruplamasının anahtarıdır
+type BrandCategoryKey struct {
+	Brand    string `json:"brand"`
+	Category string `json:"category"`
+}
+
+// BrandCategoryPager, products indeksindeki tüm marka × kategori kombinasyonlarını dolaşan pager'ı döner
+func BrandCategoryPager(client *elasticsearch.TypedClient, query q.Builder) *CompositePager[BrandCategoryKey] {
+	return NewCompositePager[BrandCategoryKey](client, "products", query, 0,
+		CompositeSource{Name: "brand", Field: "brand.keyword"},
+		CompositeSource{Name: "category", Field: "category.keyword", MissingBucket: true},
+	)
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/SadikSunbul/Go-Elasticsearch/q"
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/core/search"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+)
+
+/*
+	Belge Sayma:
+	Aramada kullanılan q.Builder filtresi aynen sayma için de kullanılır
+	Sadece toplam isteniyorsa _count API'si, indeks bazında dağılım ya da yaklaşık sayım
+	isteniyorsa size=0 bir arama (track_total_hits + _index üzerinde terms aggregation) kullanılır
+*/
+
+// defaultApproximateThreshold, yaklaşık sayımda tam sayılacak en fazla belge sayısıdır (Elasticsearch varsayılanı)
+const defaultApproximateThreshold = 10000
+
+// CountRequest, CountDocuments'a verilen sayma isteğidir
+type CountRequest struct {
+	Indices []string  // indeks adları, alias'lar ya da desenler (örn: "products-*"); boşsa tüm indeksler
+	Query   q.Builder // nil ise tüm belgeler sayılır
+
+	// TerminateAfter, her shard'da bu kadar belge bulununca saymayı durdurur
+	// Ucuz varlık kontrolü için 1 verilir; bu durumda sonuç bir alt sınırdır
+	TerminateAfter int
+
+	// ByIndex, sonucu indeks bazında da döner (örn: products-2026.09, products-2026.10)
+	ByIndex bool
+
+	// Approximate, belgeleri ApproximateThreshold'a kadar tam, sonrasını alt sınır olarak sayar
+	// Büyük indekslerde tam sayımdan daha ucuzdur
+	Approximate          bool
+	ApproximateThreshold int // 0 ise 10000
+}
+
+// CountResult, sayma sonucudur
+type CountResult struct {
+	Count int64
+
+	// Exact, Count kesin sayı ise true; terminate_after ya da yaklaşık sayım nedeniyle
+	// Count bir alt sınırsa (gerçek sayı >= Count) false olur
+	Exact bool
+
+	ByIndex map[string]int64 // sadece CountRequest.ByIndex true ise dolar
+}
+
+// CountDocuments, sorguyla eşleşen belgeleri sayar
+func CountDocuments(client *elasticsearch.TypedClient, req CountRequest) (*CountResult, error) {
+	if req.ByIndex || req.Approximate {
+		return countWithSearch(client, req)
+	}
+
+	ctx := context.Background()
+
+	count := client.Count()
+	if len(req.Indices) > 0 {
+		count.Index(strings.Join(req.Indices, ","))
+	}
+	if req.Query != nil {
+		query, err := req.Query.Build()
+		if err != nil {
+			return nil, err
+		}
+		count.Query(query)
+	}
+	if req.TerminateAfter > 0 {
+		count.TerminateAfter(strconv.Itoa(req.TerminateAfter))
+	}
+
+	res, err := count.Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CountResult{
+		Count: res.Count,
+		Exact: req.TerminateAfter == 0,
+	}, nil
+}
+
+// DocumentExists, sorguyla eşleşen en az bir belge olup olmadığını ucuz bir sayımla kontrol eder
+func DocumentExists(client *elasticsearch.TypedClient, query q.Builder, indices ...string) (bool, error) {
+	res, err := CountDocuments(client, CountRequest{
+		Indices:        indices,
+		Query:          query,
+		TerminateAfter: 1,
+	})
+	if err != nil {
+		return false, err
+	}
+	return res.Count > 0, nil
+}
+
+// countWithSearch, indeks dağılımı ve yaklaşık sayım için size=0 bir arama çalıştırır
+func countWithSearch(client *elasticsearch.TypedClient, req CountRequest) (*CountResult, error) {
+	ctx := context.Background()
+
+	size := 0
+	request := &search.Request{
+		Size:           &size,
+		TrackTotalHits: true,
+	}
+	if req.Query != nil {
+		query, err := req.Query.Build()
+		if err != nil {
+			return nil, err
+		}
+		request.Query = query
+	}
+	if req.Approximate {
+		threshold := req.ApproximateThreshold
+		if threshold <= 0 {
+			threshold = defaultApproximateThreshold
+		}
+		request.TrackTotalHits = threshold
+	}
+	if req.TerminateAfter > 0 {
+		terminateAfter := int64(req.TerminateAfter)
+		request.TerminateAfter = &terminateAfter
+	}
+	if req.ByIndex {
+		// _index meta alanı üzerinde terms aggregation, eşleşen belgeleri indeks bazında gruplar
+		field := "_index"
+		bucketSize := 10000
+		request.Aggregations = map[string]types.Aggregations{
+			"by_index": {
+				Terms: &types.TermsAggregation{Field: &field, Size: &bucketSize},
+			},
+		}
+	}
+
+	searchRequest := client.Search().Request(request)
+	if len(req.Indices) > 0 {
+		searchRequest.Index(strings.Join(req.Indices, ","))
+	}
+
+	res, err := searchRequest.Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CountResult{Exact: req.TerminateAfter == 0}
+	if res.Hits.Total != nil {
+		result.Count = res.Hits.Total.Value
+		if res.Hits.Total.Relation.Name != "eq" {
+			result.Exact = false
+		}
+	}
+
+	if req.ByIndex {
+		byIndex, err := decodeIndexBuckets(res.Aggregations["by_index"])
+		if err != nil {
+			return nil, err
+		}
+		result.ByIndex = byIndex
+	}
+	return result, nil
+}
+
+// decodeIndexBuckets, _index terms aggregation'ını indeks -> belge sayısı haritasına çevirir
+func decodeIndexBuckets(aggregate types.Aggregate) (map[string]int64, error) {
+	buckets, err := stringTermsBuckets(aggregate)
+	if err != nil {
+		return nil, err
+	}
+
+	byIndex := make(map[string]int64, len(buckets))
+	for _, bucket := range buckets {
+		byIndex[fmt.Sprint(bucket.Key)] = bucket.DocCount
+	}
+	return byIndex, nil
+}
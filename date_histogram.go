@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/SadikSunbul/Go-Elasticsearch/q"
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/core/search"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/calendarinterval"
+)
+
+/*
+	Tarih Histogramı (date_histogram):
+	Belgeleri tarih alanına göre gün, hafta, ay gibi aralıklara böler ve her aralıktaki belge sayısını döner
+	CalendarInterval takvime duyarlıdır (ay 28-31 gün, yaz saati geçişleri dikkate alınır),
+	FixedInterval ise her zaman sabit uzunluktadır (örn: "12h", "90m")
+	From/To date math kabul eder: "now-7d/d" = 7 gün önce, gün başına yuvarlanmış
+	Yuvarlama ve aralık sınırları TimeZone'a göre hesaplanır; "bugün" İstanbul'da UTC'den 3 saat önce başlar
+	min_doc_count=0 ve extended_bounds ile belge olmayan günler de 0 sayısıyla döner, grafik boşluksuz çizilir
+*/
+
+// DateHistogramRequest, DateHistogram'a verilen istek parametreleridir
+type DateHistogramRequest struct {
+	Index string    // boşsa products
+	Field string    // tarih alanı, boşsa create_date
+	Query q.Builder // ek filtre, nil olabilir
+
+	// From ve To, aralığın sınırlarıdır (tarih ya da date math, örn: "now-30d/d", "now/d")
+	// Verilen sınırlar hem range filtresi hem de extended_bounds olarak uygulanır
+	From string
+	To   string
+
+	CalendarInterval string // "day", "week", "month", "quarter", "year" ya da "1d", "1M" ...
+	FixedInterval    string // "12h", "30m" ...; CalendarInterval ile birlikte verilemez
+
+	TimeZone string // örn: "Europe/Istanbul" ya da "+03:00", boşsa UTC
+	Format   string // KeyAsString biçimi, boşsa "yyyy-MM-dd"
+
+	// SkipEmpty, belge olmayan aralıkları sonuçtan çıkarır (min_doc_count=1)
+	SkipEmpty bool
+}
+
+// DateBucket, histogramdaki tek bir zaman aralığıdır
+type DateBucket struct {
+	Start time.Time // aralığın başlangıcı, istekteki saat diliminde
+	Key   string    // Format ile biçimlendirilmiş başlangıç (örn: "2026-10-19")
+	Count int64
+}
+
+// DateHistogram, alanın değerlerini tarih aralıklarına bölerek her aralıktaki belge sayısını döner
+func DateHistogram(client *elasticsearch.TypedClient, req DateHistogramRequest) ([]DateBucket, error) {
+	ctx := context.Background()
+
+	if req.Index == "" {
+		req.Index = "products"
+	}
+	if req.Field == "" {
+		req.Field = "create_date"
+	}
+	if req.Format == "" {
+		req.Format = "yyyy-MM-dd"
+	}
+	if (req.CalendarInterval == "") == (req.FixedInterval == "") {
+		return nil, fmt.Errorf("CalendarInterval ve FixedInterval'den tam olarak biri verilmelidir")
+	}
+
+	location := time.UTC
+	if req.TimeZone != "" {
+		loc, err := parseTimeZone(req.TimeZone)
+		if err != nil {
+			return nil, err
+		}
+		location = loc
+	}
+
+	histogram := &types.DateHistogramAggregation{
+		Field:  &req.Field,
+		Format: &req.Format,
+	}
+	if req.CalendarInterval != "" {
+		histogram.CalendarInterval = &calendarinterval.CalendarInterval{Name: req.CalendarInterval}
+	} else {
+		histogram.FixedInterval = req.FixedInterval
+	}
+	if req.TimeZone != "" {
+		histogram.TimeZone = &req.TimeZone
+	}
+
+	minDocCount := 0
+	if req.SkipEmpty {
+		minDocCount = 1
+	}
+	histogram.MinDocCount = &minDocCount
+
+	filter := q.Bool()
+	if req.Query != nil {
+		filter.Filter(req.Query)
+	}
+	if req.From != "" || req.To != "" {
+		dateRange := q.Range(req.Field)
+		bounds := &types.ExtendedBoundsFieldDateMath{}
+		if req.From != "" {
+			dateRange.Gte(req.From)
+			bounds.Min = req.From
+		}
+		if req.To != "" {
+			dateRange.Lte(req.To)
+			bounds.Max = req.To
+		}
+		if req.TimeZone != "" {
+			dateRange.TimeZone(req.TimeZone)
+		}
+		filter.Filter(dateRange)
+		histogram.ExtendedBounds = bounds
+	}
+
+	size := 0
+	request := &search.Request{
+		Size: &size,
+		Aggregations: map[string]types.Aggregations{
+			"histogram": {DateHistogram: histogram},
+		},
+	}
+	if !filter.IsEmpty() {
+		query, err := filter.Build()
+		if err != nil {
+			return nil, err
+		}
+		request.Query = query
+	}
+
+	res, err := client.Search().Index(req.Index).Request(request).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	aggregate, ok := res.Aggregations["histogram"].(*types.DateHistogramAggregate)
+	if !ok {
+		return nil, fmt.Errorf("beklenmeyen aggregation tipi: %T", res.Aggregations["histogram"])
+	}
+	buckets, ok := aggregate.Buckets.([]types.DateHistogramBucket)
+	if !ok {
+		return nil, fmt.Errorf("beklenmeyen bucket tipi: %T", aggregate.Buckets)
+	}
+
+	result := make([]DateBucket, 0, len(buckets))
+	for _, bucket := range buckets {
+		dateBucket := DateBucket{
+			Start: time.UnixMilli(bucket.Key).In(location),
+			Count: bucket.DocCount,
+		}
+		if bucket.KeyAsString != nil {
+			dateBucket.Key = *bucket.KeyAsString
+		}
+		result = append(result, dateBucket)
+	}
+	return result, nil
+}
+
+// ProductsAddedPerDay, son days gün içinde her gün eklenen ürün sayısını döner (bugün dahil)
+// Ürün eklenmeyen günler de 0 sayısıyla listede yer alır
+func ProductsAddedPerDay(client *elasticsearch.TypedClient, days int, timeZone string) ([]DateBucket, error) {
+	// days < 1 iken "now--1d/d" gibi geçersiz bir date math üretilir ve Elasticsearch 400 döner
+	if days < 1 {
+		return nil, fmt.Errorf("days en az 1 olmalıdır: %d", days)
+	}
+	return DateHistogram(client, DateHistogramRequest{
+		Field:            "create_date",
+		From:             fmt.Sprintf("now-%dd/d", days-1),
+		To:               "now/d",
+		CalendarInterval: "day",
+		TimeZone:         timeZone,
+	})
+}
+
+// parseTimeZone, IANA adı (Europe/Istanbul) ya da ofset (+03:00) biçimindeki saat dilimini çözer
+func parseTimeZone(timeZone string) (*time.Location, error) {
+	if offset, err := time.Parse("-07:00", timeZone); err == nil {
+		_, seconds := offset.Zone()
+		return time.FixedZone(timeZone, seconds), nil
+	}
+	location, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return nil, fmt.Errorf("geçersiz saat dilimi %q: %w", timeZone, err)
+	}
+	return location, nil
+}
@@ -0,0 +1,12 @@
+package main
+
+import "testing"
+
+func TestProductsAddedPerDayInvalidDays(t *testing.T) {
+	// İstek gönderilmeden hata dönmeli; nil client kullanılırsa panic olur
+	for _, days := range []int{0, -7} {
+		if buckets, err := ProductsAddedPerDay(nil, days, "Europe/Istanbul"); err == nil {
+			t.Errorf("days %d: hata bekleniyordu, sonuç %v", days, buckets)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/SadikSunbul/Go-Elasticsearch/dlq"
+	"github.com/elastic/go-elasticsearch/v8"
+)
+
+// defaultReplayAttempts, replay'de bir belgenin en fazla kaç kez gönderileceğidir
+// Sınıra ulaşan belgeler dosyada kalır ve elle incelenmelidir
+const defaultReplayAttempts = 5
+
+// runDLQCommand, "dlq list|replay" komutlarını çalıştırır
+// replay, dosyaya yazan yükleyici bittikten sonra çalıştırılmalıdır (bkz. dlq.Replay)
+//
+//	go run . dlq list 09-Get-Documents/failed_documents.ndjson
+//	go run . dlq replay 09-Get-Documents/failed_documents.ndjson [en fazla deneme]
+func runDLQCommand(client *elasticsearch.TypedClient, args []string) error {
+	usage := fmt.Errorf("kullanım: dlq list <dosya> | replay <dosya> [en fazla deneme]")
+	if len(args) < 2 {
+		return usage
+	}
+
+	switch {
+	case args[0] == "list" && len(args) == 2:
+		entries, err := dlq.ReadFile(args[1])
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			fmt.Printf("%s/%s [%s, %d deneme] %s\n", entry.Index, entry.ID, entry.Kind, entry.Attempts, entry.Error)
+		}
+		return nil
+	case args[0] == "replay" && len(args) <= 3:
+		maxAttempts := defaultReplayAttempts
+		if len(args) == 3 {
+			n, err := strconv.Atoi(args[2])
+			if err != nil {
+				return usage
+			}
+			maxAttempts = n
+		}
+		result, err := dlq.Replay(args[1], maxAttempts, dlq.IndexSubmitter(client))
+		if err != nil {
+			return err
+		}
+		fmt.Println(result)
+		return nil
+	default:
+		return usage
+	}
+}
@@ -0,0 +1,284 @@
+/*
+Package dlq, indekslenemeyen belgelerin kaybolmaması için bir dead-letter kuyruğudur
+
+Yükleyiciler başarısız olan her belgeyi (ağ hatası, mapping hatası, version conflict, 429) hatası,
+hata türü ve deneme sayısıyla bir Sink'e yazar; varsayılan FileSink satır başına bir JSON nesnesi (NDJSON) yazar.
+Replay, dosyadaki belgeleri sonradan tekrar gönderir ve hâlâ başarısız olanları dosyada bırakır
+
+	sink, err := dlq.NewFileSink("failed_documents.ndjson")
+	...
+	if _, err := client.Index("my_index").Id(id).Document(doc).Do(ctx); err != nil {
+		sink.Write(dlq.NewEntry("my_index", id, doc, err, 1))
+	}
+*/
+package dlq
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/SadikSunbul/Go-Elasticsearch/breaker"
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/optype"
+)
+
+// Kind, başarısızlığın türüdür; tekrar denemenin anlamlı olup olmadığını gösterir
+type Kind string
+
+const (
+	Network         Kind = "network"          // bağlantı kurulamadı ya da zaman aşımı; tekrar denenebilir
+	RateLimited     Kind = "rate_limited"     // 429, küme yük altında; beklenip tekrar denenebilir
+	Unavailable     Kind = "unavailable"      // 502/503/504 ya da circuit breaker açık; tekrar denenebilir
+	Mapping         Kind = "mapping"          // belge mapping'e uymuyor; belge düzeltilmeden başarılı olmaz
+	VersionConflict Kind = "version_conflict" // 409, belge başka biri tarafından değiştirildi
+	Other           Kind = "other"
+)
+
+// Retryable, bu türdeki hataların belge değişmeden tekrar denendiğinde başarılı olabileceğini belirtir
+func (k Kind) Retryable() bool {
+	return k == Network || k == RateLimited || k == Unavailable
+}
+
+// Classify, hatanın türünü belirler
+func Classify(err error) Kind {
+	var esErr *types.ElasticsearchError
+	if errors.As(err, &esErr) {
+		switch esErr.Status {
+		case 409:
+			return VersionConflict
+		case 429:
+			return RateLimited
+		case 502, 503, 504:
+			return Unavailable
+		case 400:
+			switch esErr.ErrorCause.Type {
+			case "mapper_parsing_exception", "document_parsing_exception", "strict_dynamic_mapping_exception":
+				return Mapping
+			}
+		}
+		return Other
+	}
+
+	// Breaker açıkken istek hiç gönderilmemiştir; küme toparlandığında tekrar denenebilir
+	if errors.Is(err, breaker.ErrOpen) {
+		return Unavailable
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded) {
+		return Network
+	}
+	return Other
+}
+
+// Entry, dead-letter kuyruğundaki başarısız bir belgedir
+type Entry struct {
+	Index    string          `json:"index"`
+	ID       string          `json:"id,omitempty"` // boşsa tekrar gönderimde yeni ID üretilir
+	Document json.RawMessage `json:"document"`
+	Error    string          `json:"error"`
+	Kind     Kind            `json:"kind"`
+	Status   int             `json:"status,omitempty"` // Elasticsearch'ten dönen HTTP durumu
+	Attempts int             `json:"attempts"`         // belgenin kaç kez teslim edilmeye çalışıldığı; HTTP tekrarları sayılmaz
+	FailedAt time.Time       `json:"failed_at"`
+}
+
+// NewEntry, başarısız bir belge için kayıt oluşturur; doc JSON'a çevrilebilen bir değer ya da json.RawMessage olabilir
+// attempts teslim denemesi sayısıdır: yükleyicinin ilk gönderimi 1, her Replay bir fazlası. Transport'un
+// aynı gönderim içinde yaptığı HTTP tekrarları (retry paketi) buna dahil değildir, yoksa 429/503 gibi
+// geçici hatalar daha ilk kayıtta Replay'in deneme sınırına ulaşırdı
+func NewEntry(index, id string, doc interface{}, err error, attempts int) Entry {
+	entry := Entry{
+		Index:    index,
+		ID:       id,
+		Error:    err.Error(),
+		Kind:     Classify(err),
+		Attempts: attempts,
+		FailedAt: time.Now().UTC(),
+	}
+	var esErr *types.ElasticsearchError
+	if errors.As(err, &esErr) {
+		entry.Status = esErr.Status
+	}
+
+	switch d := doc.(type) {
+	case json.RawMessage:
+		entry.Document = d
+	case []byte:
+		entry.Document = d
+	default:
+		body, marshalErr := json.Marshal(doc)
+		if marshalErr != nil {
+			// Belge JSON'a çevrilemiyorsa en azından hata kaybolmasın
+			body, _ = json.Marshal(fmt.Sprintf("%+v", doc))
+		}
+		entry.Document = body
+	}
+	return entry
+}
+
+// Sink, başarısız belgelerin yazıldığı yerdir (dosya, başka bir indeks, mesaj kuyruğu...)
+type Sink interface {
+	Write(entry Entry) error
+}
+
+// FileSink, kayıtları bir NDJSON dosyasının sonuna ekler; eşzamanlı kullanıma uygundur
+type FileSink struct {
+	mu    sync.Mutex
+	file  *os.File
+	count int
+}
+
+// NewFileSink, path'teki dosyayı ekleme modunda açar, yoksa oluşturur
+func NewFileSink(path string) (*FileSink, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileSink{file: file}, nil
+}
+
+// Write, kaydı dosyaya tek satır olarak yazar
+func (s *FileSink) Write(entry Entry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.file.Write(line); err != nil {
+		return err
+	}
+	s.count++
+	return nil
+}
+
+// Count, bu sink'e yazılan kayıt sayısını döner
+func (s *FileSink) Count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.count
+}
+
+// Close, dosyayı kapatır
+func (s *FileSink) Close() error {
+	return s.file.Close()
+}
+
+// ReadFile, NDJSON dosyasındaki tüm kayıtları okur; dosya yoksa boş liste döner
+func ReadFile(path string) ([]Entry, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("%s:%d okunamadı: %w", path, line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// ReplayResult, Replay'in sonucudur
+type ReplayResult struct {
+	Succeeded int // tekrar gönderilip indekslenen
+	Failed    int // tekrar başarısız olan, dosyada kalan
+	Skipped   int // deneme sınırına ulaşmış, gönderilmeden dosyada kalan
+}
+
+func (r ReplayResult) String() string {
+	return fmt.Sprintf("%d başarılı, %d tekrar başarısız, %d atlandı", r.Succeeded, r.Failed, r.Skipped)
+}
+
+// Replay, dosyadaki belgeleri submit ile tekrar gönderir ve dosyayı sadece hâlâ başarısız olanlarla yeniden yazar
+// Deneme sayısı maxAttempts'e ulaşmış kayıtlar gönderilmez; maxAttempts 0 ise sınır yoktur
+// Mapping hataları gibi tekrar denenemeyen kayıtlar da gönderilir, belge dosyada elle düzeltilmiş olabilir
+//
+// Dosya kilitlenmez: Replay, aynı dosyaya yazan bir yükleyici çalışırken çalıştırılmamalıdır. Dosya okunup
+// yeniden yazılmış kopyası yerine taşındığı için arada eklenen kayıtlar kaybolur; dosyayı açık tutan bir
+// FileSink de taşımadan sonra silinmiş eski dosyaya yazmaya devam eder
+func Replay(path string, maxAttempts int, submit func(Entry) error) (*ReplayResult, error) {
+	entries, err := ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReplayResult{}
+	var remaining []Entry
+	for _, entry := range entries {
+		if maxAttempts > 0 && entry.Attempts >= maxAttempts {
+			result.Skipped++
+			remaining = append(remaining, entry)
+			continue
+		}
+
+		if err := submit(entry); err != nil {
+			result.Failed++
+			failed := NewEntry(entry.Index, entry.ID, entry.Document, err, entry.Attempts+1)
+			remaining = append(remaining, failed)
+			continue
+		}
+		result.Succeeded++
+	}
+
+	// Yarıda kesilirse kayıt kaybolmasın diye önce geçici dosyaya yazılıp sonra yerine taşınır
+	tmp := path + ".tmp"
+	sink, err := NewFileSink(tmp)
+	if err != nil {
+		return result, err
+	}
+	if err := sink.file.Truncate(0); err != nil {
+		sink.Close()
+		return result, err
+	}
+	for _, entry := range remaining {
+		if err := sink.Write(entry); err != nil {
+			sink.Close()
+			return result, err
+		}
+	}
+	if err := sink.Close(); err != nil {
+		return result, err
+	}
+	return result, os.Rename(tmp, path)
+}
+
+// IndexSubmitter, kaydı kendi indeksine ve ID'siyle tekrar indeksleyen submit fonksiyonunu döner
+// VersionConflict kayıtları op_type=create ile gönderilir: 409'un koruduğu daha yeni belge hâlâ duruyorsa
+// üzerine yazılmaz, kayıt tekrar 409 alıp dosyada kalır ve elle incelenir
+func IndexSubmitter(client *elasticsearch.TypedClient) func(Entry) error {
+	return func(entry Entry) error {
+		req := client.Index(entry.Index).Raw(bytes.NewReader(entry.Document))
+		if entry.ID != "" {
+			req.Id(entry.ID)
+		}
+		if entry.Kind == VersionConflict {
+			req.OpType(optype.Create)
+		}
+		_, err := req.Do(context.Background())
+		return err
+	}
+}
@@ -0,0 +1,194 @@
+package dlq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/SadikSunbul/Go-Elasticsearch/breaker"
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+)
+
+func esError(status int, errType string) error {
+	return &types.ElasticsearchError{Status: status, ErrorCause: types.ErrorCause{Type: errType}}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want Kind
+	}{
+		{"409", esError(409, "version_conflict_engine_exception"), VersionConflict},
+		{"429", esError(429, "es_rejected_execution_exception"), RateLimited},
+		{"503", esError(503, "unavailable_shards_exception"), Unavailable},
+		{"mapping", esError(400, "document_parsing_exception"), Mapping},
+		{"strict mapping", esError(400, "strict_dynamic_mapping_exception"), Mapping},
+		{"diğer 400", esError(400, "illegal_argument_exception"), Other},
+		{"sarmalanmış", fmt.Errorf("indeksleme: %w", esError(429, "")), RateLimited},
+		{"ağ", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, Network},
+		{"zaman aşımı", context.DeadlineExceeded, Network},
+		{"breaker açık", fmt.Errorf("%w (son hata: HTTP 503)", breaker.ErrOpen), Unavailable},
+		{"bilinmeyen", errors.New("bozuk"), Other},
+	}
+	for _, tt := range tests {
+		if got := Classify(tt.err); got != tt.want {
+			t.Errorf("%s: Classify = %s, %s olmalı", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNewEntry(t *testing.T) {
+	entry := NewEntry("products", "1", map[string]interface{}{"name": "Air Max"}, esError(429, ""), 1)
+	if entry.Kind != RateLimited || entry.Status != 429 || entry.Attempts != 1 {
+		t.Errorf("kayıt %+v", entry)
+	}
+	if string(entry.Document) != `{"name":"Air Max"}` {
+		t.Errorf("belge %s", entry.Document)
+	}
+
+	// json.RawMessage tekrar kodlanmadan saklanır
+	raw := json.RawMessage(`{"name": "Air Max"}`)
+	if entry := NewEntry("products", "1", raw, errors.New("x"), 1); string(entry.Document) != string(raw) {
+		t.Errorf("belge %s, %s olmalı", entry.Document, raw)
+	}
+}
+
+func TestFileSinkRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "failed.ndjson")
+
+	if entries, err := ReadFile(path); err != nil || entries != nil {
+		t.Fatalf("olmayan dosya: %v, %v", entries, err)
+	}
+
+	sink, err := NewFileSink(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"1", "2"} {
+		if err := sink.Write(NewEntry("products", id, map[string]string{"id": id}, esError(503, ""), 1)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if sink.Count() != 2 {
+		t.Errorf("Count = %d, 2 olmalı", sink.Count())
+	}
+	sink.Close()
+
+	entries, err := ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].ID != "1" || entries[1].ID != "2" || entries[1].Kind != Unavailable {
+		t.Errorf("okunan kayıtlar %+v", entries)
+	}
+}
+
+func writeEntries(t *testing.T, entries ...Entry) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "failed.ndjson")
+	sink, err := NewFileSink(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+	for _, entry := range entries {
+		if err := sink.Write(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return path
+}
+
+func TestReplay(t *testing.T) {
+	transient := esError(503, "")
+	path := writeEntries(t,
+		NewEntry("products", "ok", map[string]int{"n": 1}, transient, 1),
+		NewEntry("products", "still-failing", map[string]int{"n": 2}, transient, 1),
+		NewEntry("products", "exhausted", map[string]int{"n": 3}, transient, 5),
+	)
+
+	var submitted []string
+	result, err := Replay(path, 5, func(entry Entry) error {
+		submitted = append(submitted, entry.ID)
+		if entry.ID == "still-failing" {
+			return esError(429, "")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Succeeded != 1 || result.Failed != 1 || result.Skipped != 1 {
+		t.Errorf("sonuç %s", result)
+	}
+	if len(submitted) != 2 {
+		t.Errorf("gönderilen %v, sınıra ulaşan kayıt gönderilmemeli", submitted)
+	}
+
+	remaining, err := ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(remaining) != 2 {
+		t.Fatalf("dosyada %d kayıt kaldı, 2 olmalı", len(remaining))
+	}
+	// Tekrar başarısız olan kaydın deneme sayısı ve hata türü güncellenir
+	if remaining[0].ID != "still-failing" || remaining[0].Attempts != 2 || remaining[0].Kind != RateLimited {
+		t.Errorf("tekrar başarısız kayıt %+v", remaining[0])
+	}
+	if remaining[1].ID != "exhausted" || remaining[1].Attempts != 5 {
+		t.Errorf("atlanan kayıt %+v", remaining[1])
+	}
+}
+
+func TestIndexSubmitter(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		requests = append(requests, r.Method+" "+r.URL.Path+"?"+r.URL.RawQuery)
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"_index":"products","_id":"1","_version":1,"result":"created","_shards":{"total":1,"successful":1,"failed":0},"_seq_no":0,"_primary_term":1}`))
+	}))
+	defer server.Close()
+
+	client, err := elasticsearch.NewTypedClient(elasticsearch.Config{Addresses: []string{server.URL}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	submit := IndexSubmitter(client)
+
+	doc := json.RawMessage(`{"name":"Air Max"}`)
+	entries := []Entry{
+		{Index: "products", ID: "1", Document: doc, Kind: Unavailable},
+		{Index: "products", ID: "1", Document: doc, Kind: VersionConflict},
+	}
+	for _, entry := range entries {
+		if err := submit(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []string{
+		"PUT /products/_doc/1?",
+		// Çakışan kayıt daha yeni belgenin üzerine yazmamalı
+		"PUT /products/_doc/1?op_type=create",
+	}
+	if len(requests) != len(want) {
+		t.Fatalf("istekler %v, %v olmalı", requests, want)
+	}
+	for i := range want {
+		if requests[i] != want[i] {
+			t.Errorf("%d. istek %q, %q olmalı", i+1, requests[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+)
+
+// isStatus, hata Elasticsearch'ten verilen HTTP durum koduyla dönmüşse true döner
+func isStatus(err error, status int) bool {
+	var esErr *types.ElasticsearchError
+	return errors.As(err, &esErr) && esErr.Status == status
+}
+
+// isNotFound, hata 404 (indeks, belge, script vb. bulunamadı) ise true döner
+func isNotFound(err error) bool {
+	return isStatus(err, 404)
+}
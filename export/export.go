@@ -0,0 +1,296 @@
+/*
+Package export, bir sorguyla eşleşen tüm belgeleri CSV ya da TSV olarak dışa aktarır
+
+Belgeler point in time (PIT) ve search_after ile sayfa sayfa okunur ve okundukça yazılır;
+from/size'ın 10000 belge sınırına takılmaz ve dışa aktarım sürerken eklenen belgeler sonucu kaydırmaz
+
+	n, err := export.Export(client, file, export.Options{
+		Index:   "products",
+		Query:   q.Term("brand.keyword", "Nike"),
+		Columns: []export.Column{{Field: "name"}, {Field: "price", Header: "Fiyat"}},
+		Numbers: export.TurkishNumbers,
+	})
+
+İç içe nesneler nokta ile düzleştirilir (örn: author.first_name), dizi değerleri "|" ile birleştirilir
+*/
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/SadikSunbul/Go-Elasticsearch/q"
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/core/closepointintime"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/core/search"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/sortorder"
+)
+
+// Format, çıktı dosyasının biçimidir
+type Format int
+
+const (
+	CSV Format = iota // virgülle ayrılmış değerler
+	TSV               // sekmeyle ayrılmış değerler, Excel'e yapıştırmak için uygundur
+)
+
+// IDField, Column.Field olarak verildiğinde belgenin _id'sini yazar
+const IDField = "_id"
+
+const (
+	defaultPageSize  = 1000
+	defaultKeepAlive = "1m"
+	arraySeparator   = "|"
+)
+
+// Column, çıktıdaki tek bir sütundur
+type Column struct {
+	Field  string // belgedeki alanın nokta ile ayrılmış yolu (örn: author.first_name) ya da IDField
+	Header string // başlık satırındaki ad, boşsa Field
+}
+
+// Options, Export parametreleridir
+type Options struct {
+	Index string    // indeks, alias ya da desen
+	Query q.Builder // nil ise tüm belgeler
+
+	// Columns, yazılacak sütunlardır; boşsa ilk sayfadaki belgelerin tüm alanları alfabetik sırayla yazılır
+	// (sonraki sayfalarda ilk kez görülen alanlar yazılmaz)
+	Columns []Column
+
+	Format  Format
+	Numbers NumberFormat // sayıların biçimi, sıfır değeri PlainNumbers ile aynıdır
+
+	// ExcelBOM, dosyanın başına UTF-8 BOM yazar; Excel Türkçe karakterleri ancak bu şekilde doğru açar
+	// Bu modda = + - @ ile başlayan metin hücrelerinin başına ' eklenir, Excel bunları formül olarak çalıştırmaz
+	ExcelBOM bool
+
+	PageSize  int    // sayfa başına okunacak belge sayısı, 0 ise 1000
+	KeepAlive string // PIT'in sayfalar arası açık kalma süresi, boşsa "1m"
+}
+
+// Export, sorguyla eşleşen tüm belgeleri w'ye yazar ve yazılan belge sayısını döner
+func Export(client *elasticsearch.TypedClient, w io.Writer, options Options) (int64, error) {
+	ctx := context.Background()
+
+	if options.PageSize <= 0 {
+		options.PageSize = defaultPageSize
+	}
+	if options.KeepAlive == "" {
+		options.KeepAlive = defaultKeepAlive
+	}
+
+	if options.ExcelBOM {
+		if _, err := w.Write([]byte("\xEF\xBB\xBF")); err != nil {
+			return 0, err
+		}
+	}
+
+	writer := csv.NewWriter(w)
+	if options.Format == TSV {
+		writer.Comma = '\t'
+	}
+
+	pit, err := client.OpenPointInTime(options.Index).KeepAlive(options.KeepAlive).Do(ctx)
+	if err != nil {
+		return 0, err
+	}
+	pitID := pit.Id
+	defer func() {
+		// PIT kapatılmazsa keep_alive süresi dolana kadar sunucuda kaynak tutar
+		client.ClosePointInTime().Request(&closepointintime.Request{Id: pitID}).Do(ctx)
+	}()
+
+	columns := options.Columns
+	var written int64
+	var searchAfter []types.FieldValue
+
+	for {
+		request := &search.Request{
+			Size: &options.PageSize,
+			Pit:  &types.PointInTimeReference{Id: pitID, KeepAlive: options.KeepAlive},
+			// _shard_doc, PIT ile en ucuz ve benzersiz sıralamadır
+			Sort: []types.SortCombinations{
+				types.SortOptions{SortOptions: map[string]types.FieldSort{
+					"_shard_doc": {Order: &sortorder.Asc},
+				}},
+			},
+			SearchAfter: searchAfter,
+		}
+		if options.Query != nil {
+			if request.Query, err = options.Query.Build(); err != nil {
+				return written, err
+			}
+		}
+
+		// PIT kullanıldığında indeks istek yolunda verilmez, PIT'ten alınır
+		res, err := client.Search().Request(request).Do(ctx)
+		if err != nil {
+			return written, err
+		}
+		if res.PitId != nil {
+			pitID = *res.PitId
+		}
+
+		hits := res.Hits.Hits
+		if len(hits) == 0 {
+			break
+		}
+
+		page := make([]map[string]interface{}, len(hits))
+		for i, hit := range hits {
+			fields, err := Flatten(hit.Source_)
+			if err != nil {
+				return written, err
+			}
+			if hit.Id_ != nil {
+				fields[IDField] = *hit.Id_
+			}
+			page[i] = fields
+		}
+
+		if written == 0 {
+			if len(columns) == 0 {
+				columns = discoverColumns(page)
+			}
+			if err := writeHeader(writer, columns); err != nil {
+				return written, err
+			}
+		}
+
+		for _, fields := range page {
+			record := make([]string, len(columns))
+			for i, column := range columns {
+				value := fields[column.Field]
+				record[i] = options.Numbers.formatValue(value)
+				if _, isNumber := value.(json.Number); options.ExcelBOM && !isNumber {
+					record[i] = escapeFormula(record[i])
+				}
+			}
+			if err := writer.Write(record); err != nil {
+				return written, err
+			}
+			written++
+		}
+
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return written, err
+		}
+
+		if len(hits) < options.PageSize {
+			break
+		}
+		searchAfter = hits[len(hits)-1].Sort
+	}
+
+	// Hiç belge yoksa sadece başlık yazılır (sütunlar biliniyorsa)
+	if written == 0 && len(columns) > 0 {
+		if err := writeHeader(writer, columns); err != nil {
+			return 0, err
+		}
+	}
+	writer.Flush()
+	return written, writer.Error()
+}
+
+func writeHeader(writer *csv.Writer, columns []Column) error {
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = column.Header
+		if header[i] == "" {
+			header[i] = column.Field
+		}
+	}
+	return writer.Write(header)
+}
+
+// discoverColumns, sütun verilmediğinde sayfadaki belgelerin tüm alanlarının birleşiminden
+// _id önde olacak şekilde sütun listesi çıkarır; ilk belgede olmayan alanlar da yazılır
+func discoverColumns(page []map[string]interface{}) []Column {
+	seen := map[string]bool{IDField: true}
+	var names []string
+	for _, fields := range page {
+		for name := range fields {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+
+	columns := []Column{{Field: IDField}}
+	for _, name := range names {
+		columns = append(columns, Column{Field: name})
+	}
+	return columns
+}
+
+// formulaPrefixes, Excel'in hücre başında formül olarak yorumladığı karakterlerdir (tab ve CR dahil)
+const formulaPrefixes = "=+-@\t\r"
+
+// escapeFormula, formül gibi başlayan hücrenin başına ' ekler; Excel hücreyi metin olarak gösterir
+// (örn: =HYPERLINK(...) içeren bir ürün adı dosya açıldığında çalışmaz)
+func escapeFormula(cell string) string {
+	if cell != "" && strings.ContainsRune(formulaPrefixes, rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
+
+// Flatten, JSON belgeyi nokta ile ayrılmış alan yolu -> değer haritasına düzleştirir
+// {"author": {"first_name": "Imad"}} -> {"author.first_name": "Imad"}
+// Dizilerdeki değerler (nesne dizileri dahil) aynı yolda []interface{} olarak toplanır
+func Flatten(source json.RawMessage) (map[string]interface{}, error) {
+	fields := map[string]interface{}{}
+	if len(source) == 0 {
+		return fields, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(source))
+	decoder.UseNumber() // büyük tam sayılar float64'e çevrilirken hassasiyet kaybetmesin
+	var document map[string]interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return nil, fmt.Errorf("belge düzleştirilemedi: %w", err)
+	}
+
+	flattenInto(fields, "", document)
+	return fields, nil
+}
+
+func flattenInto(fields map[string]interface{}, path string, value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			flattenInto(fields, childPath, child)
+		}
+	case []interface{}:
+		for _, item := range v {
+			flattenInto(fields, path, item)
+		}
+	default:
+		existing, ok := fields[path]
+		if !ok {
+			fields[path] = v
+			return
+		}
+		// Aynı yolda ikinci değer: diziye çevrilir (örn: user.first -> ["John", "Imad"])
+		if list, isList := existing.([]interface{}); isList {
+			fields[path] = append(list, v)
+		} else {
+			fields[path] = []interface{}{existing, v}
+		}
+	}
+}
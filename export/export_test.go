@@ -0,0 +1,89 @@
+package export
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/elastic/go-elasticsearch/v8"
+)
+
+// fakeCluster, PIT açma/kapama isteklerine ve tek sayfalık aramaya hits ile yanıt veren bir küme başlatır
+func fakeCluster(t *testing.T, hits string) *elasticsearch.TypedClient {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method + " " + r.URL.Path {
+		case "POST /products/_pit":
+			w.Write([]byte(`{"id":"pit-1"}`))
+		case "POST /_search":
+			w.Write([]byte(`{"took":1,"timed_out":false,"_shards":{"total":1,"successful":1,"skipped":0,"failed":0},` +
+				`"pit_id":"pit-1","hits":{"total":{"value":3,"relation":"eq"},"hits":` + hits + `}}`))
+		case "DELETE /_pit":
+			w.Write([]byte(`{"succeeded":true,"num_freed":1}`))
+		default:
+			t.Errorf("beklenmeyen istek %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := elasticsearch.NewTypedClient(elasticsearch.Config{Addresses: []string{server.URL}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestExport(t *testing.T) {
+	client := fakeCluster(t, `[
+		{"_index":"products","_id":"1","_source":{"name":"Air Max","price":1299.99},"sort":[1]},
+		{"_index":"products","_id":"2","_source":{"name":"=HYPERLINK(\"http://x\")","brand":"@Nike","price":-10},"sort":[2]},
+		{"_index":"products","_id":"3","_source":{"name":"-Ultraboost","tags":["+yeni","indirim"]},"sort":[3]}
+	]`)
+
+	tests := []struct {
+		name    string
+		options Options
+		want    string
+	}{
+		{
+			// Sütunlar sayfadaki tüm belgelerin alanlarından çıkarılır, ilk belgede olmayan brand ve tags de yazılır
+			"sütun birleşimi",
+			Options{Index: "products"},
+			"_id,brand,name,price,tags\n" +
+				"1,,Air Max,1299.99,\n" +
+				"2,@Nike,\"=HYPERLINK(\"\"http://x\"\")\",-10,\n" +
+				"3,,-Ultraboost,,+yeni|indirim\n",
+		},
+		{
+			// Excel modunda formül gibi başlayan metinler kaçırılır, negatif sayılar sayı olarak kalır
+			"excel formül koruması",
+			Options{Index: "products", Format: TSV, Numbers: TurkishNumbers, ExcelBOM: true,
+				Columns: []Column{{Field: IDField}, {Field: "name"}, {Field: "brand"}, {Field: "price"}, {Field: "tags"}}},
+			"\xEF\xBB\xBF_id\tname\tbrand\tprice\ttags\n" +
+				"1\tAir Max\t\t1.299,99\t\n" +
+				"2\t\"'=HYPERLINK(\"\"http://x\"\")\"\t'@Nike\t-10\t\n" +
+				"3\t'-Ultraboost\t\t\t'+yeni|indirim\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			n, err := Export(client, &out, tt.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != 3 {
+				t.Errorf("%d belge yazıldı, 3 olmalı", n)
+			}
+			if out.String() != tt.want {
+				t.Errorf("\n%q\nolmalı:\n%q", out.String(), tt.want)
+			}
+		})
+	}
+}
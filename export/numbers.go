@@ -0,0 +1,102 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// NumberFormat, sayıların yerel ayara göre yazımıdır
+// Türkçe Excel "1.299,99" biçimini sayı olarak tanır, "1299.99" ise metin olarak açılır
+type NumberFormat struct {
+	DecimalSeparator   string // ondalık ayırıcı, boşsa "."
+	ThousandsSeparator string // binlik ayırıcı, boşsa kullanılmaz
+	Decimals           int    // ondalık basamak sayısı, 0 ise gerektiği kadar (tam sayılar ondalıksız yazılır)
+}
+
+var (
+	// PlainNumbers, sayıları Elasticsearch'ten geldiği gibi yazar: 1299.99
+	PlainNumbers = NumberFormat{}
+	// TurkishNumbers, sayıları Türkçe yazar: 1.299,99
+	TurkishNumbers = NumberFormat{DecimalSeparator: ",", ThousandsSeparator: "."}
+	// EnglishNumbers, sayıları İngilizce yazar: 1,299.99
+	EnglishNumbers = NumberFormat{DecimalSeparator: ".", ThousandsSeparator: ","}
+)
+
+// formatValue, düzleştirilmiş bir alan değerini hücre metnine çevirir
+func (f NumberFormat) formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case json.Number:
+		return f.Format(v)
+	case []interface{}:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = f.formatValue(item)
+		}
+		return strings.Join(parts, arraySeparator)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Format, JSON sayısını yerel ayara göre yazar
+func (f NumberFormat) Format(number json.Number) string {
+	text := number.String()
+
+	// Tam sayılar float64'e çevrilmeden yazılır, büyük id'ler bozulmaz
+	if f.Decimals == 0 && !strings.ContainsAny(text, ".eE") {
+		return f.group(text, "")
+	}
+
+	value, err := number.Float64()
+	if err != nil {
+		return text
+	}
+	precision := -1
+	if f.Decimals > 0 {
+		precision = f.Decimals
+	}
+	text = strconv.FormatFloat(value, 'f', precision, 64)
+
+	integer, fraction, _ := strings.Cut(text, ".")
+	return f.group(integer, fraction)
+}
+
+// group, tam sayı kısmına binlik ayırıcıları ekler ve ondalık kısmı ayırıcıyla birleştirir
+func (f NumberFormat) group(integer, fraction string) string {
+	sign := ""
+	if strings.HasPrefix(integer, "-") {
+		sign, integer = "-", integer[1:]
+	}
+
+	if f.ThousandsSeparator != "" && len(integer) > 3 {
+		var builder strings.Builder
+		head := len(integer) % 3
+		if head > 0 {
+			builder.WriteString(integer[:head])
+		}
+		for i := head; i < len(integer); i += 3 {
+			if builder.Len() > 0 {
+				builder.WriteString(f.ThousandsSeparator)
+			}
+			builder.WriteString(integer[i : i+3])
+		}
+		integer = builder.String()
+	}
+
+	if fraction == "" {
+		return sign + integer
+	}
+	decimal := f.DecimalSeparator
+	if decimal == "" {
+		decimal = "."
+	}
+	return sign + integer + decimal + fraction
+}
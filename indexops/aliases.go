@@ -0,0 +1,178 @@
+package indexops
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/indices/updatealiases"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+)
+
+/*
+	Alias'lar:
+	Servisler fiziksel indeks adı (products-v3) yerine sabit bir alias'a (products) okur ve yazar
+	Yeni mapping gerektiğinde products-v4 oluşturulup doldurulur, ardından SwapAlias ile alias'lar tek
+	bir atomik istekte yeni indekse taşınır; servislerin yapılandırması değişmez
+
+	Filtreli alias, sadece filtreye uyan belgeleri gösterir (örn: her kiracı için products-<kiracı>)
+	Routing alias, okuma ve yazmaları belirli shard'lara yönlendirir; sadece belgeler baştan aynı
+	routing değeriyle yazıldıysa kullanılmalıdır, yoksa aramada belgeler kaçırılır
+*/
+
+// Alias, bir indeksin alias tanımıdır
+type Alias struct {
+	Name  string
+	Index string
+
+	Filter        *types.Query // boş değilse alias sadece bu sorguya uyan belgeleri gösterir
+	Routing       string       // hem okuma hem yazma için routing
+	IndexRouting  string       // sadece yazma için routing; Routing'i ezer
+	SearchRouting string       // sadece okuma için routing; Routing'i ezer (virgülle birden fazla değer)
+
+	// IsWriteIndex, alias birden fazla indekse işaret ederken yazmaların gideceği indeksi belirler
+	// nil ise alias tek indekse işaret ettiğinde o indeks yazma indeksidir
+	IsWriteIndex *bool
+}
+
+func (a Alias) String() string {
+	var details []string
+	if a.Filter != nil {
+		details = append(details, "filtreli")
+	}
+	if a.Routing != "" {
+		details = append(details, "routing="+a.Routing)
+	}
+	if a.IndexRouting != "" {
+		details = append(details, "index_routing="+a.IndexRouting)
+	}
+	if a.SearchRouting != "" {
+		details = append(details, "search_routing="+a.SearchRouting)
+	}
+	if a.IsWriteIndex != nil && *a.IsWriteIndex {
+		details = append(details, "yazma indeksi")
+	}
+	if len(details) == 0 {
+		return fmt.Sprintf("%s -> %s", a.Name, a.Index)
+	}
+	return fmt.Sprintf("%s -> %s (%s)", a.Name, a.Index, strings.Join(details, ", "))
+}
+
+func (a Alias) addAction() types.IndicesAction {
+	action := &types.AddAction{
+		Alias:        &a.Name,
+		Index:        &a.Index,
+		Filter:       a.Filter,
+		IsWriteIndex: a.IsWriteIndex,
+	}
+	if a.Routing != "" {
+		action.Routing = &a.Routing
+	}
+	if a.IndexRouting != "" {
+		action.IndexRouting = &a.IndexRouting
+	}
+	if a.SearchRouting != "" {
+		action.SearchRouting = &a.SearchRouting
+	}
+	return types.IndicesAction{Add: action}
+}
+
+func removeAliasAction(index, name string) types.IndicesAction {
+	return types.IndicesAction{Remove: &types.RemoveAction{Alias: &name, Index: &index}}
+}
+
+// AddAliases, alias'ları tek bir atomik istekte ekler; aynı ad ve indeksli alias varsa üzerine yazılır
+func AddAliases(client *elasticsearch.TypedClient, aliases ...Alias) (*Result, error) {
+	actions := make([]types.IndicesAction, 0, len(aliases))
+	names := make([]string, 0, len(aliases))
+	for _, alias := range aliases {
+		actions = append(actions, alias.addAction())
+		names = append(names, alias.Name)
+	}
+	return updateAliases(client, strings.Join(names, ","), "alias add", actions)
+}
+
+// RemoveAlias, alias'ı indeksten kaldırır; index "*" ise alias tüm indekslerden kaldırılır
+func RemoveAlias(client *elasticsearch.TypedClient, index, name string) (*Result, error) {
+	return updateAliases(client, name, "alias remove", []types.IndicesAction{removeAliasAction(index, name)})
+}
+
+// SwapAlias, her alias'ı şu an işaret ettiği tüm indekslerden kaldırıp kendi Index'ine ekler
+// Tüm kaldırma ve eklemeler tek istekte yapıldığı için hiçbir alias hiçbir an boşta kalmaz ve
+// birlikte taşınan alias'lar (örn: products ve kiracı alias'ları) hiçbir an farklı indekslere işaret etmez
+// Filtre ve routing eski tanımlardan taşınmaz, alias'ta verilenler kullanılır
+func SwapAlias(client *elasticsearch.TypedClient, aliases ...Alias) (*Result, error) {
+	if len(aliases) == 0 {
+		return nil, fmt.Errorf("taşınacak alias verilmedi")
+	}
+	names := make([]string, 0, len(aliases))
+	targets := make(map[string]string, len(aliases))
+	for _, alias := range aliases {
+		names = append(names, alias.Name)
+		targets[alias.Name] = alias.Index
+	}
+
+	current, err := ListAliases(client, strings.Join(names, ","))
+	if err != nil {
+		return nil, err
+	}
+
+	actions := make([]types.IndicesAction, 0, len(current)+len(aliases))
+	for _, existing := range current {
+		if target, ok := targets[existing.Name]; ok && existing.Index != target {
+			actions = append(actions, removeAliasAction(existing.Index, existing.Name))
+		}
+	}
+	for _, alias := range aliases {
+		actions = append(actions, alias.addAction())
+	}
+	return updateAliases(client, strings.Join(names, ","), "alias swap -> "+aliases[0].Index, actions)
+}
+
+// ListAliases, name (alias adı, deseni ya da virgülle ayrılmış listesi, örn: "products", "products-*", "*") ile eşleşen
+// alias'ları alias ve indeks adına göre sıralı döner; eşleşen alias yoksa boş liste döner
+func ListAliases(client *elasticsearch.TypedClient, name string) ([]Alias, error) {
+	res, err := client.Indices.GetAlias().Name(name).Do(context.Background())
+	if err != nil {
+		if isStatus(err, 404) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var aliases []Alias
+	for index, indexAliases := range res {
+		for aliasName, definition := range indexAliases.Aliases {
+			aliases = append(aliases, Alias{
+				Name:          aliasName,
+				Index:         index,
+				Filter:        definition.Filter,
+				Routing:       stringValue(definition.Routing),
+				IndexRouting:  stringValue(definition.IndexRouting),
+				SearchRouting: stringValue(definition.SearchRouting),
+				IsWriteIndex:  definition.IsWriteIndex,
+			})
+		}
+	}
+	sort.Slice(aliases, func(i, j int) bool {
+		if aliases[i].Name != aliases[j].Name {
+			return aliases[i].Name < aliases[j].Name
+		}
+		return aliases[i].Index < aliases[j].Index
+	})
+	return aliases, nil
+}
+
+func updateAliases(client *elasticsearch.TypedClient, name, operation string, actions []types.IndicesAction) (*Result, error) {
+	start := time.Now()
+	res, err := client.Indices.UpdateAliases().
+		Request(&updatealiases.Request{Actions: actions}).
+		Do(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Index: name, Operation: operation, Acknowledged: res.Acknowledged, Took: time.Since(start)}, nil
+}
@@ -0,0 +1,337 @@
+package indexops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/ilm/putlifecycle"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/indices/create"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+)
+
+/*
+	ILM (Index Lifecycle Management) ve Rollover:
+	Olay tipi veriler tek bir my_index yerine events-000001, events-000002... gibi indekslere yazılır
+	Uygulama her zaman "events" alias'ına yazar; alias'ın is_write_index'i en yeni indekstir
+	hot fazında indeks max_age ya da max_size'a ulaşınca ILM rollover yapar: yeni indeks oluşur ve alias ona geçer
+	warm fazında eski indeksler tek segmente birleştirilip salt okunur yapılır, delete fazında silinir
+
+	Politika, index template'in index.lifecycle.name ayarıyla eşleşen indekslere bağlanır (LifecycleSettings)
+	İlk indeks template'ten otomatik oluşamaz; BootstrapWriteIndex ile alias'la birlikte bir kez oluşturulur
+*/
+
+// policyVersionMetaKey, ILM politikasının Go tarafındaki sürümünün _meta'da tutulduğu anahtardır
+// Elasticsearch'ün politika version'ı her yazmada kendiliğinden arttığı için kullanılamaz
+const policyVersionMetaKey = "version"
+
+// LifecyclePolicy, hot/warm/delete fazlarından oluşan ILM politikasıdır
+type LifecyclePolicy struct {
+	Name    string
+	Version int64
+
+	// hot: bu koşullardan biri sağlanınca rollover yapılır (boş olanlar kullanılmaz)
+	RolloverMaxAge  string // örn: "7d"
+	RolloverMaxSize string // örn: "50gb"
+	RolloverMaxDocs int64
+
+	// warm: rollover'dan WarmAfter kadar sonra indeks salt okunur yapılır ve birleştirilir; boşsa warm fazı yok
+	WarmAfter         string // örn: "30d"
+	WarmMergeSegments int    // 0 ise forcemerge yapılmaz
+	WarmReplicas      *int   // nil ise replica sayısı değişmez
+
+	// delete: rollover'dan DeleteAfter kadar sonra indeks silinir; boşsa silinmez
+	DeleteAfter string // örn: "90d"
+}
+
+// LifecycleSettings, index template'e eklenerek politikayı eşleşen indekslere bağlayan ayarlardır
+func LifecycleSettings(policy, rolloverAlias string) *types.IndexSettings {
+	return &types.IndexSettings{
+		Lifecycle: &types.IndexSettingsLifecycle{
+			Name:          &policy,
+			RolloverAlias: &rolloverAlias,
+		},
+	}
+}
+
+// PutLifecyclePolicy, politikayı kümeye yazar (varsa üzerine yazar)
+func PutLifecyclePolicy(client *elasticsearch.TypedClient, policy LifecyclePolicy) (*Result, error) {
+	start := time.Now()
+	request, err := policy.request()
+	if err != nil {
+		return nil, err
+	}
+	res, err := client.Ilm.PutLifecycle(policy.Name).Request(request).Do(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Index: policy.Name, Operation: "ilm policy", Acknowledged: res.Acknowledged, Took: time.Since(start)}, nil
+}
+
+func (p LifecyclePolicy) request() (*putlifecycle.Request, error) {
+	phases := types.Phases{}
+
+	rollover := &types.RolloverAction{}
+	if p.RolloverMaxAge != "" {
+		rollover.MaxAge = p.RolloverMaxAge
+	}
+	if p.RolloverMaxSize != "" {
+		rollover.MaxSize = p.RolloverMaxSize
+	}
+	if p.RolloverMaxDocs > 0 {
+		rollover.MaxDocs = &p.RolloverMaxDocs
+	}
+	if rollover.MaxAge == nil && rollover.MaxSize == nil && rollover.MaxDocs == nil {
+		return nil, fmt.Errorf("%s politikası için en az bir rollover koşulu gereklidir", p.Name)
+	}
+	phases.Hot = &types.Phase{Actions: &types.IlmActions{Rollover: rollover}}
+
+	if p.WarmAfter != "" {
+		actions := &types.IlmActions{Readonly: &types.EmptyObject{}}
+		if p.WarmMergeSegments > 0 {
+			actions.Forcemerge = &types.ForceMergeAction{MaxNumSegments: p.WarmMergeSegments}
+		}
+		if p.WarmReplicas != nil {
+			actions.Allocate = &types.AllocateAction{NumberOfReplicas: p.WarmReplicas}
+		}
+		phases.Warm = &types.Phase{MinAge: phaseAge(p.WarmAfter), Actions: actions}
+	}
+
+	if p.DeleteAfter != "" {
+		phases.Delete = &types.Phase{
+			MinAge:  phaseAge(p.DeleteAfter),
+			Actions: &types.IlmActions{Delete: &types.DeleteAction{}},
+		}
+	}
+
+	policy := &types.IlmPolicy{Phases: phases}
+	meta, err := checksumMeta(policy)
+	if err != nil {
+		return nil, err
+	}
+	meta[policyVersionMetaKey] = json.RawMessage(strconv.FormatInt(p.Version, 10))
+	policy.Meta_ = meta
+
+	return &putlifecycle.Request{Policy: policy}, nil
+}
+
+func phaseAge(age string) *types.Duration {
+	var duration types.Duration = age
+	return &duration
+}
+
+// policyState, kümedeki politikanın _meta'sını okur; politika yoksa nil döner
+func policyState(client *elasticsearch.TypedClient, name string) (*storedTemplate, error) {
+	res, err := client.Ilm.GetLifecycle().Policy(name).Perform(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode >= 300 {
+		return nil, fmt.Errorf("HTTP %d: %s", res.StatusCode, body)
+	}
+
+	var raw map[string]struct {
+		Policy struct {
+			Meta types.Metadata `json:"_meta"`
+		} `json:"policy"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, err
+	}
+	policy, ok := raw[name]
+	if !ok {
+		return nil, nil
+	}
+
+	stored := &storedTemplate{Meta: policy.Policy.Meta}
+	if version, ok := policy.Policy.Meta[policyVersionMetaKey]; ok {
+		var v int64
+		if err := json.Unmarshal(version, &v); err == nil {
+			stored.Version = &v
+		}
+	}
+	return stored, nil
+}
+
+// BootstrapWriteIndex, rollover alias'ının ilk indeksini (örn: events-000001) alias'ın yazma indeksi olarak oluşturur
+// Alias zaten varsa bir şey yapmaz; ayarlar ve mapping indeks adıyla eşleşen template'ten gelir
+func BootstrapWriteIndex(client *elasticsearch.TypedClient, alias string) (*Result, error) {
+	ctx := context.Background()
+	start := time.Now()
+
+	exists, err := client.Indices.ExistsAlias(alias).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return &Result{Index: alias, Operation: "bootstrap", Acknowledged: true, Took: time.Since(start)}, nil
+	}
+
+	// Rollover, sondaki sayıyı artırarak yeni indeks adını üretir; tarih kullanmak için
+	// "<events-{now/d}-000001>" gibi date math adları da kullanılabilir
+	index := alias + "-000001"
+	isWriteIndex := true
+	res, err := client.Indices.Create(index).
+		Request(&create.Request{
+			Aliases: map[string]types.Alias{alias: {IsWriteIndex: &isWriteIndex}},
+		}).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Index: index, Operation: "bootstrap", Acknowledged: res.Acknowledged && res.ShardsAcknowledged, Took: time.Since(start)}, nil
+}
+
+// RolloverResult, rollover isteğinin sonucudur
+type RolloverResult struct {
+	Alias      string
+	OldIndex   string
+	NewIndex   string
+	RolledOver bool // DryRun'da ya da koşullar sağlanmadığında false
+	DryRun     bool
+	Conditions map[string]bool // koşul -> sağlandı mı (örn: "[max_age: 7d]": true)
+}
+
+// RolloverOptions, elle tetiklenen rollover'ın koşullarıdır; hepsi boşsa rollover koşulsuz yapılır
+type RolloverOptions struct {
+	MaxAge  string
+	MaxSize string
+	MaxDocs int64
+	DryRun  bool // sadece koşulları kontrol et, rollover yapma
+}
+
+// Rollover, alias'ı yeni bir indekse geçirir (ILM'i beklemeden elle tetikleme ya da DryRun ile kontrol için)
+func Rollover(client *elasticsearch.TypedClient, alias string, options RolloverOptions) (*RolloverResult, error) {
+	conditions := &types.RolloverConditions{}
+	hasCondition := false
+	if options.MaxAge != "" {
+		conditions.MaxAge = options.MaxAge
+		hasCondition = true
+	}
+	if options.MaxSize != "" {
+		conditions.MaxSize = options.MaxSize
+		hasCondition = true
+	}
+	if options.MaxDocs > 0 {
+		conditions.MaxDocs = &options.MaxDocs
+		hasCondition = true
+	}
+
+	req := client.Indices.Rollover(alias).DryRun(options.DryRun)
+	if hasCondition {
+		req.Conditions(conditions)
+	}
+	res, err := req.Do(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return &RolloverResult{
+		Alias:      alias,
+		OldIndex:   res.OldIndex,
+		NewIndex:   res.NewIndex,
+		RolledOver: res.RolledOver,
+		DryRun:     res.DryRun,
+		Conditions: res.Conditions,
+	}, nil
+}
+
+// LifecycleStatus, bir indeksin ILM içindeki anlık durumudur
+type LifecycleStatus struct {
+	Index   string
+	Managed bool
+	Policy  string
+	Age     string // indeksin oluşturulmasından (ya da rollover'dan) bu yana geçen süre
+	Phase   string // hot, warm, delete
+	Action  string // örn: rollover, forcemerge
+	Step    string // örn: check-rollover-ready, ERROR
+	Failed  string // Step ERROR ise başarısız olan adım
+	Reason  string // Step ERROR ise hata açıklaması
+}
+
+func (s LifecycleStatus) String() string {
+	if !s.Managed {
+		return fmt.Sprintf("%s: ILM tarafından yönetilmiyor", s.Index)
+	}
+	status := fmt.Sprintf("%s: politika %s, yaş %s, %s/%s/%s", s.Index, s.Policy, s.Age, s.Phase, s.Action, s.Step)
+	if s.Failed != "" {
+		status += fmt.Sprintf(" (%s adımı başarısız: %s)", s.Failed, s.Reason)
+	}
+	return status
+}
+
+// ExplainLifecycle, index (ad, alias ya da desen) ile eşleşen indekslerin ILM durumunu ada göre sıralı döner
+func ExplainLifecycle(client *elasticsearch.TypedClient, index string) ([]LifecycleStatus, error) {
+	res, err := client.Ilm.ExplainLifecycle(index).Perform(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode >= 300 {
+		errorResponse := types.NewElasticsearchError()
+		if err := json.Unmarshal(body, errorResponse); err != nil {
+			return nil, fmt.Errorf("ILM durumu okunamadı (HTTP %d): %s", res.StatusCode, body)
+		}
+		if errorResponse.Status == 0 {
+			errorResponse.Status = res.StatusCode
+		}
+		return nil, errorResponse
+	}
+
+	// Yönetilen ve yönetilmeyen indeksler farklı şekilde döndüğü için tipli union yerine düz yapı okunur
+	var raw struct {
+		Indices map[string]struct {
+			Managed    bool   `json:"managed"`
+			Policy     string `json:"policy"`
+			Age        string `json:"age"`
+			Phase      string `json:"phase"`
+			Action     string `json:"action"`
+			Step       string `json:"step"`
+			FailedStep string `json:"failed_step"`
+			StepInfo   struct {
+				Reason string `json:"reason"`
+			} `json:"step_info"`
+		} `json:"indices"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, err
+	}
+
+	statuses := make([]LifecycleStatus, 0, len(raw.Indices))
+	for name, explain := range raw.Indices {
+		statuses = append(statuses, LifecycleStatus{
+			Index:   name,
+			Managed: explain.Managed,
+			Policy:  explain.Policy,
+			Age:     explain.Age,
+			Phase:   explain.Phase,
+			Action:  explain.Action,
+			Step:    explain.Step,
+			Failed:  explain.FailedStep,
+			Reason:  explain.StepInfo.Reason,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Index < statuses[j].Index
+	})
+	return statuses, nil
+}
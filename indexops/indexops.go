@@ -0,0 +1,250 @@
+/*
+Package indexops, oluşturulmuş bir indeksin işletimi için yardımcı fonksiyonlar içerir:
+dinamik ayarların güncellenmesi (replica sayısı, refresh_interval), refresh, flush, forcemerge,
+open/close, yazma/okuma blokları ve alias'lar. Kümedeki tanımlar da buradan yönetilir:
+ingest pipeline'lar, index/component template'ler, ILM politikaları ile rollover ve snapshot/restore
+
+Her işlem, hangi indekste ne yapıldığını, onaylanıp onaylanmadığını ve shard sonuçlarını
+içeren bir *Result döner; Status ise indeksin anlık durumunu raporlar
+
+	err := indexops.WithRefreshDisabled(client, "my_index", func() error {
+		return loadDocuments(client)
+	})
+*/
+package indexops
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+)
+
+// Block, indekse eklenebilecek bloklardır
+type Block string
+
+const (
+	ReadOnly Block = "read_only" // yazma ve metadata değişikliği engellenir, indeks silinebilir
+	Write    Block = "write"     // sadece yazma engellenir, metadata değiştirilebilir
+	Read     Block = "read"      // okuma engellenir
+	Metadata Block = "metadata"  // mapping/ayar okuma ve değiştirme engellenir
+)
+
+// Blocks, Status'ta raporlanan tüm blok tipleridir
+var Blocks = []Block{ReadOnly, Write, Read, Metadata}
+
+// ShardResult, shard bazında çalışan işlemlerin (refresh, flush, forcemerge) sonucudur
+type ShardResult struct {
+	Total      uint
+	Successful uint
+	Failed     uint
+}
+
+// Result, tek bir indeks işleminin sonucudur
+type Result struct {
+	Index        string
+	Operation    string
+	Acknowledged bool
+	Shards       *ShardResult // sadece shard bazında çalışan işlemlerde dolar
+	Took         time.Duration
+}
+
+func (r Result) String() string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "%s %s: ", r.Operation, r.Index)
+	if r.Shards != nil {
+		fmt.Fprintf(&builder, "%d/%d shard başarılı", r.Shards.Successful, r.Shards.Total)
+		if r.Shards.Failed > 0 {
+			fmt.Fprintf(&builder, ", %d başarısız", r.Shards.Failed)
+		}
+	} else if r.Acknowledged {
+		builder.WriteString("onaylandı")
+	} else {
+		builder.WriteString("onaylanmadı")
+	}
+	fmt.Fprintf(&builder, " (%s)", r.Took.Round(time.Millisecond))
+	return builder.String()
+}
+
+// ShardFailureError, işlem bazı shard'larda başarısız olduğunda döner
+type ShardFailureError struct {
+	Result *Result
+	Reason string
+}
+
+func (e *ShardFailureError) Error() string {
+	return fmt.Sprintf("%s %s: %d shard başarısız: %s", e.Result.Operation, e.Result.Index, e.Result.Shards.Failed, e.Reason)
+}
+
+// UpdateSettings, indeksin dinamik ayarlarını günceller
+// Anahtarlar "index." öneki olmadan ya da önekle verilebilir (örn: "number_of_replicas")
+// nil değer, ayarı varsayılanına döndürür
+func UpdateSettings(client *elasticsearch.TypedClient, index string, settings map[string]interface{}) (*Result, error) {
+	ctx := context.Background()
+	start := time.Now()
+
+	body, err := json.Marshal(map[string]interface{}{"index": settings})
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Indices.PutSettings().Indices(index).Raw(bytes.NewReader(body)).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Index: index, Operation: "settings", Acknowledged: res.Acknowledged, Took: time.Since(start)}, nil
+}
+
+// SetReplicas, indeksin replica sayısını değiştirir
+func SetReplicas(client *elasticsearch.TypedClient, index string, replicas int) (*Result, error) {
+	return UpdateSettings(client, index, map[string]interface{}{"number_of_replicas": replicas})
+}
+
+// SetRefreshInterval, indeksin refresh aralığını değiştirir
+// "-1" refresh'i kapatır, boş string varsayılana (1s) döndürür
+func SetRefreshInterval(client *elasticsearch.TypedClient, index, interval string) (*Result, error) {
+	var value interface{}
+	if interval != "" {
+		value = interval
+	}
+	return UpdateSettings(client, index, map[string]interface{}{"refresh_interval": value})
+}
+
+// WithRefreshDisabled, fn çalışırken indeksin refresh'ini kapatır, ardından önceki değeri geri yükler
+// ve indeksi bir kez refresh eder. Toplu yüklemelerde her saniye yeni segment oluşmasını engeller
+// fn hata dönse bile ayar geri yüklenir
+func WithRefreshDisabled(client *elasticsearch.TypedClient, index string, fn func() error) (err error) {
+	// index bir alias ya da desen olabilir, her indeksin kendi değeri saklanır
+	settings, err := flatSettings(client, index)
+	if err != nil {
+		return err
+	}
+	previous := make(map[string]string, len(settings))
+	for name, values := range settings {
+		previous[name] = values["index.refresh_interval"]
+	}
+
+	if _, err := SetRefreshInterval(client, index, "-1"); err != nil {
+		return err
+	}
+
+	defer func() {
+		for name, interval := range previous {
+			if _, restoreErr := SetRefreshInterval(client, name, interval); restoreErr != nil && err == nil {
+				err = fmt.Errorf("%s refresh_interval geri yüklenemedi: %w", name, restoreErr)
+			}
+		}
+		if _, refreshErr := Refresh(client, index); refreshErr != nil && err == nil {
+			err = refreshErr
+		}
+	}()
+
+	return fn()
+}
+
+// Refresh, son yazılan belgeleri aramada görünür yapar
+func Refresh(client *elasticsearch.TypedClient, index string) (*Result, error) {
+	start := time.Now()
+	res, err := client.Indices.Refresh().Index(index).Do(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return shardResult(index, "refresh", res.Shards_, start)
+}
+
+// Flush, bellekteki ve translog'daki işlemleri kalıcı olarak diske yazar
+func Flush(client *elasticsearch.TypedClient, index string) (*Result, error) {
+	start := time.Now()
+	res, err := client.Indices.Flush().Index(index).Do(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return shardResult(index, "flush", res.Shards_, start)
+}
+
+// ForceMerge, indeksin her shard'ını en fazla maxSegments segmente birleştirir
+// Sadece artık yazılmayan indekslerde (örn: geçmiş ayın log indeksi) kullanılmalıdır
+func ForceMerge(client *elasticsearch.TypedClient, index string, maxSegments int) (*Result, error) {
+	start := time.Now()
+	req := client.Indices.Forcemerge().Index(index)
+	if maxSegments > 0 {
+		req.MaxNumSegments(strconv.Itoa(maxSegments))
+	}
+	res, err := req.Do(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return shardResult(index, "forcemerge", res.Shards_, start)
+}
+
+// Open, kapalı bir indeksi açar
+func Open(client *elasticsearch.TypedClient, index string) (*Result, error) {
+	start := time.Now()
+	res, err := client.Indices.Open(index).Do(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Index: index, Operation: "open", Acknowledged: res.Acknowledged && res.ShardsAcknowledged, Took: time.Since(start)}, nil
+}
+
+// Close, indeksi kapatır; kapalı indeks okunamaz ve yazılamaz ama diskte durur
+func Close(client *elasticsearch.TypedClient, index string) (*Result, error) {
+	start := time.Now()
+	res, err := client.Indices.Close(index).Do(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Index: index, Operation: "close", Acknowledged: res.Acknowledged && res.ShardsAcknowledged, Took: time.Since(start)}, nil
+}
+
+// AddBlock, indekse verilen bloğu ekler (örn: taşıma öncesi Write ile yazmayı durdurmak)
+func AddBlock(client *elasticsearch.TypedClient, index string, block Block) (*Result, error) {
+	start := time.Now()
+	res, err := client.Indices.AddBlock(index, string(block)).Do(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Index: index, Operation: "block " + string(block), Acknowledged: res.Acknowledged && res.ShardsAcknowledged, Took: time.Since(start)}, nil
+}
+
+// RemoveBlock, indeksteki bloğu kaldırır
+func RemoveBlock(client *elasticsearch.TypedClient, index string, block Block) (*Result, error) {
+	result, err := UpdateSettings(client, index, map[string]interface{}{"blocks." + string(block): nil})
+	if err != nil {
+		return nil, err
+	}
+	result.Operation = "unblock " + string(block)
+	return result, nil
+}
+
+// shardResult, shard istatistiklerini Result'a çevirir; başarısız shard varsa ShardFailureError döner
+func shardResult(index, operation string, shards *types.ShardStatistics, start time.Time) (*Result, error) {
+	result := &Result{Index: index, Operation: operation, Acknowledged: true, Took: time.Since(start)}
+	if shards == nil {
+		return result, nil
+	}
+
+	result.Shards = &ShardResult{Total: shards.Total, Successful: shards.Successful, Failed: shards.Failed}
+	if shards.Failed > 0 {
+		result.Acknowledged = false
+		reason := "bilinmiyor"
+		if len(shards.Failures) > 0 && shards.Failures[0].Reason.Reason != nil {
+			reason = *shards.Failures[0].Reason.Reason
+		}
+		return result, &ShardFailureError{Result: result, Reason: reason}
+	}
+	return result, nil
+}
+
+// isStatus, hata Elasticsearch'ten verilen HTTP durum koduyla dönmüşse true döner
+func isStatus(err error, status int) bool {
+	var esErr *types.ElasticsearchError
+	return errors.As(err, &esErr) && esErr.Status == status
+}
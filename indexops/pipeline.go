@@ -0,0 +1,266 @@
+package indexops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/ingest/putpipeline"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/ingest/simulate"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/converttype"
+)
+
+/*
+	Ingest Pipeline'lar:
+	Belgeler indekslenmeden önce Elasticsearch'te sırayla processor'lardan geçer
+	(örn: "100" string fiyatı float'a çevirme, markayı küçük harfe çevirme, boşlukları kırpma)
+	Bir pipeline, indeksin index.default_pipeline ayarı ile o indekse yazılan her belgeye uygulanır
+
+	Simulate, pipeline'ı kümeye yazmadan örnek belgeler üzerinde çalıştırıp dönüşmüş belgeleri döner;
+	pipeline tanımları bu sayede testlerde doğrulanabilir
+
+	Processor yardımcıları ignore_missing ile oluşturulur: kirli veride alan eksikse pipeline durmaz
+*/
+
+// Pipeline, Go tarafında tanımlanan bir ingest pipeline'ıdır
+type Pipeline struct {
+	Name        string
+	Version     int64
+	Description string
+	Processors  []types.ProcessorContainer
+
+	// OnFailure, herhangi bir processor hata verdiğinde belgeyi reddetmek yerine çalıştırılır
+	// Hata veren processor'dan sonraki processor'lar atlanır; belge o haliyle indekslenir ve hâlâ
+	// mapping'e uymayan bir alan içeriyorsa reddedilir. Tek bir alanın hatası için DropOnFailure kullanılır
+	OnFailure []types.ProcessorContainer
+}
+
+func (p Pipeline) request() (*putpipeline.Request, error) {
+	request := &putpipeline.Request{
+		Description: &p.Description,
+		Processors:  p.Processors,
+		OnFailure:   p.OnFailure,
+		Version:     &p.Version,
+	}
+	meta, err := checksumMeta(request)
+	if err != nil {
+		return nil, err
+	}
+	request.Meta_ = meta
+	return request, nil
+}
+
+// PutPipeline, pipeline'ı kümeye yazar (varsa üzerine yazar)
+func PutPipeline(client *elasticsearch.TypedClient, pipeline Pipeline) (*Result, error) {
+	request, err := pipeline.request()
+	if err != nil {
+		return nil, err
+	}
+	return putPipeline(client, pipeline.Name, request)
+}
+
+func putPipeline(client *elasticsearch.TypedClient, name string, request *putpipeline.Request) (*Result, error) {
+	start := time.Now()
+	res, err := client.Ingest.PutPipeline(name).Request(request).Do(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Index: name, Operation: "pipeline", Acknowledged: res.Acknowledged, Took: time.Since(start)}, nil
+}
+
+// SetDefaultPipeline, indekse yazılan her belgenin pipeline'dan geçmesini sağlar; boş string pipeline'ı kaldırır
+func SetDefaultPipeline(client *elasticsearch.TypedClient, index, pipeline string) (*Result, error) {
+	var value interface{}
+	if pipeline != "" {
+		value = pipeline
+	}
+	return UpdateSettings(client, index, map[string]interface{}{"default_pipeline": value})
+}
+
+// pipelineState, kümedeki pipeline'ın version ve _meta'sını okur; pipeline yoksa nil döner
+func pipelineState(client *elasticsearch.TypedClient, name string) (*storedTemplate, error) {
+	res, err := client.Ingest.GetPipeline().Id(name).Perform(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode >= 300 {
+		return nil, fmt.Errorf("HTTP %d: %s", res.StatusCode, body)
+	}
+
+	var raw map[string]storedTemplate
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, err
+	}
+	stored, ok := raw[name]
+	if !ok {
+		return nil, nil
+	}
+	return &stored, nil
+}
+
+// SimulatedDocument, Simulate'te bir örnek belgenin pipeline'dan çıkmış halidir
+type SimulatedDocument struct {
+	Source json.RawMessage // dönüşmüş _source
+	Err    error           // processor hatası (OnFailure yoksa)
+}
+
+// Decode, dönüşmüş belgeyi v'ye çözer
+func (d SimulatedDocument) Decode(v interface{}) error {
+	if d.Err != nil {
+		return d.Err
+	}
+	return json.Unmarshal(d.Source, v)
+}
+
+// Simulate, pipeline'ı kümeye yazmadan örnek belgeler üzerinde çalıştırır ve sonuçları aynı sırayla döner
+// Belgeler JSON'a çevrilebilen herhangi bir değer olabilir (struct, map)
+func Simulate(client *elasticsearch.TypedClient, pipeline Pipeline, docs ...interface{}) ([]SimulatedDocument, error) {
+	request := &simulate.Request{
+		Pipeline: &types.IngestPipeline{
+			Description: &pipeline.Description,
+			Processors:  pipeline.Processors,
+			OnFailure:   pipeline.OnFailure,
+		},
+	}
+	return runSimulate(client.Ingest.Simulate(), request, docs)
+}
+
+// SimulateStored, kümede kayıtlı pipeline'ı örnek belgeler üzerinde çalıştırır
+func SimulateStored(client *elasticsearch.TypedClient, name string, docs ...interface{}) ([]SimulatedDocument, error) {
+	return runSimulate(client.Ingest.Simulate().Id(name), &simulate.Request{}, docs)
+}
+
+func runSimulate(req *simulate.Simulate, request *simulate.Request, docs []interface{}) ([]SimulatedDocument, error) {
+	request.Docs = make([]types.Document, 0, len(docs))
+	for i, doc := range docs {
+		source, err := json.Marshal(doc)
+		if err != nil {
+			return nil, fmt.Errorf("%d. örnek belge JSON'a dönüştürülemedi: %w", i+1, err)
+		}
+		request.Docs = append(request.Docs, types.Document{Source_: source})
+	}
+
+	res, err := req.Request(request).Do(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]SimulatedDocument, 0, len(res.Docs))
+	for _, doc := range res.Docs {
+		var result SimulatedDocument
+		switch {
+		case doc.Error != nil:
+			result.Err = fmt.Errorf("%s: %s", doc.Error.Type, stringValue(doc.Error.Reason))
+		case doc.Doc != nil:
+			result.Source, err = json.Marshal(doc.Doc.Source_)
+			if err != nil {
+				return nil, err
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// Convert, alanı verilen tipe çevirir (örn: "100" -> 100.0)
+func Convert(field string, to converttype.ConvertType) types.ProcessorContainer {
+	return types.ProcessorContainer{Convert: &types.ConvertProcessor{Field: field, Type: to, IgnoreMissing: ignoreMissing()}}
+}
+
+// Lowercase, string alanı küçük harfe çevirir
+func Lowercase(field string) types.ProcessorContainer {
+	return types.ProcessorContainer{Lowercase: &types.LowercaseProcessor{Field: field, IgnoreMissing: ignoreMissing()}}
+}
+
+// Trim, string alanın başındaki ve sonundaki boşlukları kırpar
+func Trim(field string) types.ProcessorContainer {
+	return types.ProcessorContainer{Trim: &types.TrimProcessor{Field: field, IgnoreMissing: ignoreMissing()}}
+}
+
+// Date, alanı formatlardan ilk eşleşenle ayrıştırıp ISO 8601 olarak aynı alana yazar
+// Saat dilimi içermeyen tarihler timezone'a göre yorumlanır
+func Date(field, timezone string, formats ...string) types.ProcessorContainer {
+	return types.ProcessorContainer{Date: &types.DateProcessor{
+		Field:       field,
+		TargetField: &field,
+		Formats:     formats,
+		Timezone:    &timezone,
+		If:          fieldPresent(field),
+	}}
+}
+
+// Set, alana değer yazar; value mustache şablonu olabilir (örn: "{{{_ingest.timestamp}}}")
+func Set(field, value string) types.ProcessorContainer {
+	return types.ProcessorContainer{Set: &types.SetProcessor{Field: field, Value: stringValueJSON(value)}}
+}
+
+// SetDefault, alan yoksa ya da null ise değer yazar; varsa dokunmaz
+func SetDefault(field, value string) types.ProcessorContainer {
+	override := false
+	return types.ProcessorContainer{Set: &types.SetProcessor{Field: field, Value: stringValueJSON(value), Override: &override}}
+}
+
+// Script, painless betiğini çalıştırır; belgeye ctx üzerinden erişilir (örn: ctx.price)
+func Script(source string) types.ProcessorContainer {
+	return types.ProcessorContainer{Script: &types.ScriptProcessor{Source: &source}}
+}
+
+// Remove, alanları belgeden siler
+func Remove(fields ...string) types.ProcessorContainer {
+	return types.ProcessorContainer{Remove: &types.RemoveProcessor{Field: fields, IgnoreMissing: ignoreMissing()}}
+}
+
+// FailureField, DropOnFailure'ın processor hatasını yazdığı alandır
+const FailureField = "ingest_error"
+
+// DropOnFailure, Convert ya da Date processor'ı alanı işleyemediğinde belgeyi durdurmak yerine alanı siler,
+// hatayı FailureField'a yazar ve pipeline sonraki processor'larla devam eder
+// Örneğin "yüz lira" fiyatı float'a çevrilemezse price silinir; double mapping'i belgeyi reddetmez
+func DropOnFailure(processor types.ProcessorContainer) types.ProcessorContainer {
+	switch {
+	case processor.Convert != nil:
+		processor.Convert.OnFailure = dropField(processor.Convert.Field)
+	case processor.Date != nil:
+		processor.Date.OnFailure = dropField(processor.Date.Field)
+	}
+	return processor
+}
+
+func dropField(field string) []types.ProcessorContainer {
+	return []types.ProcessorContainer{
+		// Üçlü süslü parantez mesajı HTML kaçırmadan yazar; {{ }} ile tırnaklar &quot; olurdu
+		Set(FailureField, fmt.Sprintf("%s: {{{ _ingest.on_failure_message }}}", field)),
+		Remove(field),
+	}
+}
+
+func ignoreMissing() *bool {
+	ignore := true
+	return &ignore
+}
+
+// fieldPresent, date processor'ı ignore_missing desteklemediği için alan yoksa atlayan koşuldur
+func fieldPresent(field string) *string {
+	condition := fmt.Sprintf("ctx.containsKey('%s') && ctx['%s'] != null", field, field)
+	return &condition
+}
+
+func stringValueJSON(value string) json.RawMessage {
+	// string'in JSON'a çevrilmesi hata veremez
+	encoded, _ := json.Marshal(value)
+	return encoded
+}
@@ -0,0 +1,156 @@
+package indexops
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/converttype"
+)
+
+// fakeCluster, her isteğe response'u döner ve son isteğin yolunu ve gövdesini kaydeder
+func fakeCluster(t *testing.T, response string) (*elasticsearch.TypedClient, *string, *[]byte) {
+	t.Helper()
+	var (
+		path string
+		body []byte
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.Method + " " + r.URL.Path
+		body, _ = io.ReadAll(r.Body)
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := elasticsearch.NewTypedClient(elasticsearch.Config{Addresses: []string{server.URL}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, &path, &body
+}
+
+func TestSimulate(t *testing.T) {
+	client, path, body := fakeCluster(t, `{"docs": [
+		{"doc": {"_index": "_index", "_id": "_id", "_source": {"brand": "Nike", "price": 100.0}}},
+		{"error": {"type": "illegal_argument_exception", "reason": "unable to convert [yüz lira] to float"}}
+	]}`)
+
+	pipeline := Pipeline{
+		Name:        "products-clean",
+		Description: "test",
+		Processors:  []types.ProcessorContainer{Convert("price", converttype.Float)},
+	}
+	results, err := Simulate(client, pipeline,
+		map[string]interface{}{"brand": "Nike", "price": "100"},
+		map[string]interface{}{"brand": "Puma", "price": "yüz lira"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *path != "POST /_ingest/pipeline/_simulate" {
+		t.Errorf("istek %s", *path)
+	}
+	var request struct {
+		Pipeline struct {
+			Processors []map[string]json.RawMessage `json:"processors"`
+		} `json:"pipeline"`
+		Docs []struct {
+			Source map[string]interface{} `json:"_source"`
+		} `json:"docs"`
+	}
+	if err := json.Unmarshal(*body, &request); err != nil {
+		t.Fatal(err)
+	}
+	if len(request.Pipeline.Processors) != 1 || request.Pipeline.Processors[0]["convert"] == nil {
+		t.Errorf("pipeline %s", *body)
+	}
+	if len(request.Docs) != 2 || request.Docs[1].Source["price"] != "yüz lira" {
+		t.Errorf("örnek belgeler %s", *body)
+	}
+
+	tests := []struct {
+		name      string
+		wantPrice float64
+		wantErr   bool
+	}{
+		{"dönüşmüş belge", 100, false},
+		{"processor hatası", 0, true},
+	}
+	if len(results) != len(tests) {
+		t.Fatalf("%d sonuç, %d olmalı", len(results), len(tests))
+	}
+	for i, tt := range tests {
+		var doc struct {
+			Price float64 `json:"price"`
+		}
+		err := results[i].Decode(&doc)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: hata bekleniyordu", tt.name)
+			}
+			continue
+		}
+		if err != nil || doc.Price != tt.wantPrice {
+			t.Errorf("%s: fiyat %v (%v), %v olmalı", tt.name, doc.Price, err, tt.wantPrice)
+		}
+	}
+}
+
+func TestDropOnFailure(t *testing.T) {
+	tests := []struct {
+		name      string
+		processor types.ProcessorContainer
+		want      string
+	}{
+		{
+			"convert",
+			DropOnFailure(Convert("price", converttype.Float)),
+			`{"convert":{"field":"price","ignore_missing":true,"on_failure":[` +
+				`{"set":{"field":"ingest_error","value":"price: {{{ _ingest.on_failure_message }}}"}},` +
+				`{"remove":{"field":["price"],"ignore_missing":true}}],"type":"float"}}`,
+		},
+		{
+			"date",
+			DropOnFailure(Date("create_date", "Europe/Istanbul", "dd.MM.yyyy")),
+			`{"date":{"field":"create_date","formats":["dd.MM.yyyy"],"if":"ctx.containsKey('create_date') && ctx['create_date'] != null","on_failure":[` +
+				`{"set":{"field":"ingest_error","value":"create_date: {{{ _ingest.on_failure_message }}}"}},` +
+				`{"remove":{"field":["create_date"],"ignore_missing":true}}],"target_field":"create_date","timezone":"Europe/Istanbul"}}`,
+		},
+		{
+			// Diğer processor'lara dokunulmaz
+			"trim",
+			DropOnFailure(Trim("name")),
+			`{"trim":{"field":"name","ignore_missing":true}}`,
+		},
+	}
+	for _, tt := range tests {
+		got, err := json.Marshal(tt.processor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !jsonEqual(t, got, []byte(tt.want)) {
+			t.Errorf("%s:\n%s\nolmalı:\n%s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func jsonEqual(t *testing.T, a, b []byte) bool {
+	t.Helper()
+	var x, y interface{}
+	if err := json.Unmarshal(a, &x); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(b, &y); err != nil {
+		t.Fatal(err)
+	}
+	left, _ := json.Marshal(x)
+	right, _ := json.Marshal(y)
+	return string(left) == string(right)
+}
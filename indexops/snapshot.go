@@ -0,0 +1,254 @@
+package indexops
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/snapshot/create"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/snapshot/createrepository"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/snapshot/restore"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+)
+
+/*
+	Snapshot ve Restore:
+	Snapshot'lar bir repository'ye (burada paylaşılan dosya sistemi, "fs") yazılır
+	fs repository'nin konumu, her node'un elasticsearch.yml dosyasındaki path.repo altında olmalıdır:
+
+		path.repo: ["/var/backups/elasticsearch"]
+
+	Göreli bir location (örn: "products") path.repo'nun ilk dizinine göre çözülür
+	Snapshot'lar artımlıdır; sadece önceki snapshot'tan sonra değişen segment dosyaları kopyalanır
+	Açık bir indeksin üzerine restore yapılamaz; RenamePattern ile farklı bir ada restore edilir ya da indeks önce kapatılır
+*/
+
+// Snapshot durumları; Status API'si devam eden snapshot'lar için STARTED, Get API'si IN_PROGRESS döner
+const (
+	SnapshotInProgress = "IN_PROGRESS"
+	SnapshotStarted    = "STARTED"
+	SnapshotSuccess    = "SUCCESS"
+	SnapshotPartial    = "PARTIAL" // bazı shard'lar yedeklenemedi
+	SnapshotFailed     = "FAILED"
+)
+
+// RegisterFSRepository, location dizinini kullanan bir fs snapshot repository'si kaydeder
+// Kayıt sırasında tüm node'ların dizine yazabildiği doğrulanır
+func RegisterFSRepository(client *elasticsearch.TypedClient, name, location string) (*Result, error) {
+	start := time.Now()
+	compress := true
+	var repository createrepository.Request = types.SharedFileSystemRepository{
+		Type: "fs",
+		Settings: types.SharedFileSystemRepositorySettings{
+			Location: location,
+			Compress: &compress,
+		},
+	}
+	res, err := client.Snapshot.CreateRepository(name).Request(&repository).Do(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Index: name, Operation: "repository", Acknowledged: res.Acknowledged, Took: time.Since(start)}, nil
+}
+
+// CreateSnapshot, indekslerin (ad ya da desen) snapshot'ını başlatır ve beklemeden döner
+// İlerleme SnapshotStatus ile izlenir ya da WaitForSnapshot ile tamamlanması beklenir
+// Küme ayarları (global state) snapshot'a dahil edilmez
+func CreateSnapshot(client *elasticsearch.TypedClient, repository, snapshot string, indices ...string) (*Result, error) {
+	start := time.Now()
+	includeGlobalState := false
+	res, err := client.Snapshot.Create(repository, snapshot).
+		Request(&create.Request{
+			Indices:            indices,
+			IncludeGlobalState: &includeGlobalState,
+		}).
+		WaitForCompletion(false).
+		Do(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	accepted := res.Accepted != nil && *res.Accepted
+	return &Result{Index: repository + "/" + snapshot, Operation: "snapshot", Acknowledged: accepted, Took: time.Since(start)}, nil
+}
+
+// SnapshotProgress, bir snapshot'ın shard ve dosya bazında ilerlemesidir
+type SnapshotProgress struct {
+	Repository   string
+	Snapshot     string
+	State        string
+	ShardsDone   int64
+	ShardsFailed int64
+	ShardsTotal  int64
+	BytesDone    int64 // bu snapshot'ta kopyalanan (artımlı) boyut
+	BytesTotal   int64 // snapshot'ın toplam boyutu (önceki snapshot'larla paylaşılan dosyalar dahil)
+	Took         time.Duration
+}
+
+// Running, snapshot'ın hâlâ devam edip etmediğini döner
+func (p SnapshotProgress) Running() bool {
+	return p.State == SnapshotInProgress || p.State == SnapshotStarted || p.State == "INIT"
+}
+
+func (p SnapshotProgress) String() string {
+	return fmt.Sprintf("%s/%s [%s] shard: %d/%d (%d başarısız), kopyalanan: %d/%d bayt (%s)",
+		p.Repository, p.Snapshot, p.State, p.ShardsDone, p.ShardsTotal, p.ShardsFailed,
+		p.BytesDone, p.BytesTotal, p.Took.Round(time.Millisecond))
+}
+
+// SnapshotStatus, snapshot'ın anlık ilerlemesini döner
+func SnapshotStatus(client *elasticsearch.TypedClient, repository, snapshot string) (*SnapshotProgress, error) {
+	res, err := client.Snapshot.Status().Repository(repository).Snapshot(snapshot).Do(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Snapshots) == 0 {
+		return nil, fmt.Errorf("%s/%s snapshot'ı bulunamadı", repository, snapshot)
+	}
+
+	status := res.Snapshots[0]
+	return &SnapshotProgress{
+		Repository:   status.Repository,
+		Snapshot:     status.Snapshot,
+		State:        status.State,
+		ShardsDone:   status.ShardsStats.Done,
+		ShardsFailed: status.ShardsStats.Failed,
+		ShardsTotal:  status.ShardsStats.Total,
+		BytesDone:    status.Stats.Incremental.SizeInBytes,
+		BytesTotal:   status.Stats.Total.SizeInBytes,
+		Took:         time.Duration(status.Stats.TimeInMillis) * time.Millisecond,
+	}, nil
+}
+
+// WaitForSnapshot, snapshot bitene kadar her interval'de durumunu sorgular ve progress'e iletir
+// timeout aşılırsa ya da snapshot SUCCESS dışında bir durumla biterse hata döner
+func WaitForSnapshot(client *elasticsearch.TypedClient, repository, snapshot string, interval, timeout time.Duration, progress func(SnapshotProgress)) (*SnapshotProgress, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		status, err := SnapshotStatus(client, repository, snapshot)
+		if err != nil {
+			return nil, err
+		}
+		if progress != nil {
+			progress(*status)
+		}
+
+		if !status.Running() {
+			if status.State != SnapshotSuccess {
+				return status, fmt.Errorf("%s/%s snapshot'ı %s durumuyla bitti", repository, snapshot, status.State)
+			}
+			return status, nil
+		}
+		if time.Now().After(deadline) {
+			return status, fmt.Errorf("%s/%s snapshot'ı %s içinde bitmedi", repository, snapshot, timeout)
+		}
+		time.Sleep(interval)
+	}
+}
+
+// SnapshotSummary, repository'deki bir snapshot'ın özetidir
+type SnapshotSummary struct {
+	Name         string
+	State        string
+	Indices      []string
+	Start        time.Time
+	Duration     time.Duration
+	ShardsFailed uint
+	Reason       string // FAILED ya da PARTIAL ise nedeni
+}
+
+func (s SnapshotSummary) String() string {
+	summary := fmt.Sprintf("%s [%s] %s, süre %s, indeksler: %s", s.Name, s.State,
+		s.Start.Format(time.RFC3339), s.Duration.Round(time.Millisecond), strings.Join(s.Indices, ", "))
+	if s.ShardsFailed > 0 {
+		summary += fmt.Sprintf(" (%d shard başarısız: %s)", s.ShardsFailed, s.Reason)
+	}
+	return summary
+}
+
+// ListSnapshots, repository'deki tüm snapshot'ları eskiden yeniye sıralı döner
+func ListSnapshots(client *elasticsearch.TypedClient, repository string) ([]SnapshotSummary, error) {
+	res, err := client.Snapshot.Get(repository, "_all").Do(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]SnapshotSummary, 0, len(res.Snapshots))
+	for _, info := range res.Snapshots {
+		summary := SnapshotSummary{
+			Name:    info.Snapshot,
+			State:   stringValue(info.State),
+			Indices: info.Indices,
+			Reason:  stringValue(info.Reason),
+		}
+		sort.Strings(summary.Indices)
+		if info.StartTimeInMillis != nil {
+			summary.Start = time.UnixMilli(*info.StartTimeInMillis)
+		}
+		if info.DurationInMillis != nil {
+			summary.Duration = time.Duration(*info.DurationInMillis) * time.Millisecond
+		}
+		if info.Shards != nil {
+			summary.ShardsFailed = info.Shards.Failed
+		}
+		summaries = append(summaries, summary)
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Start.Before(summaries[j].Start)
+	})
+	return summaries, nil
+}
+
+// DeleteSnapshot, snapshot'ı repository'den siler; sadece başka snapshot'larla paylaşılmayan dosyalar silinir
+func DeleteSnapshot(client *elasticsearch.TypedClient, repository, snapshot string) (*Result, error) {
+	start := time.Now()
+	res, err := client.Snapshot.Delete(repository, snapshot).Do(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Index: repository + "/" + snapshot, Operation: "delete snapshot", Acknowledged: res.Acknowledged, Took: time.Since(start)}, nil
+}
+
+// RestoreOptions, restore edilecek indeksleri ve yeni adlarını belirler
+type RestoreOptions struct {
+	Indices []string // boşsa snapshot'taki tüm indeksler
+
+	// RenamePattern ile eşleşen indeks adları RenameReplacement ile değiştirilir
+	// örn: "(.+)" ve "restored_$1" -> products, restored_products olarak restore edilir
+	RenamePattern     string
+	RenameReplacement string
+
+	IncludeAliases bool // indekslerin alias'ları da restore edilir; yeniden adlandırmada çakışabilir
+	Wait           bool // restore bitene kadar bekle
+}
+
+// RestoreSnapshot, snapshot'taki indeksleri geri yükler
+func RestoreSnapshot(client *elasticsearch.TypedClient, repository, snapshot string, options RestoreOptions) (*Result, error) {
+	start := time.Now()
+	request := &restore.Request{
+		Indices:        options.Indices,
+		IncludeAliases: &options.IncludeAliases,
+	}
+	if options.RenamePattern != "" {
+		request.RenamePattern = &options.RenamePattern
+		request.RenameReplacement = &options.RenameReplacement
+	}
+
+	res, err := client.Snapshot.Restore(repository, snapshot).
+		Request(request).
+		WaitForCompletion(options.Wait).
+		Do(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Snapshot == nil {
+		accepted := res.Accepted != nil && *res.Accepted
+		return &Result{Index: repository + "/" + snapshot, Operation: "restore", Acknowledged: accepted, Took: time.Since(start)}, nil
+	}
+	shards := res.Snapshot.Shards
+	return shardResult(strings.Join(res.Snapshot.Indices, ","), "restore", &shards, start)
+}
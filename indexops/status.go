@@ -0,0 +1,136 @@
+package indexops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+)
+
+// defaultRefreshInterval, refresh_interval ayarlanmamış indekslerin refresh aralığıdır
+const defaultRefreshInterval = "1s"
+
+// IndexStatus, bir indeksin anlık durumudur
+type IndexStatus struct {
+	Name            string
+	Health          string // green, yellow, red
+	State           string // open, close
+	DocsCount       int64
+	PrimaryShards   int
+	Replicas        int
+	SegmentsCount   int64
+	StoreSize       string
+	RefreshInterval string // "-1" ise refresh kapalıdır
+	Blocks          []Block
+}
+
+func (s IndexStatus) String() string {
+	return fmt.Sprintf("%s [%s, %s] belge: %d, shard: %d+%d replica, segment: %d, boyut: %s, refresh: %s, bloklar: %v",
+		s.Name, s.Health, s.State, s.DocsCount, s.PrimaryShards, s.Replicas, s.SegmentsCount, s.StoreSize, s.RefreshInterval, s.Blocks)
+}
+
+// Status, index (ad, alias ya da desen) ile eşleşen indekslerin durumunu ada göre sıralı döner
+func Status(client *elasticsearch.TypedClient, index string) ([]IndexStatus, error) {
+	ctx := context.Background()
+
+	records, err := client.Cat.Indices().Index(index).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := flatSettings(client, index)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]IndexStatus, 0, len(records))
+	for _, record := range records {
+		status := indexStatusFromRecord(record)
+
+		values := settings[status.Name]
+		status.RefreshInterval = values["index.refresh_interval"]
+		if status.RefreshInterval == "" {
+			status.RefreshInterval = defaultRefreshInterval
+		}
+		for _, block := range Blocks {
+			if values["index.blocks."+string(block)] == "true" {
+				status.Blocks = append(status.Blocks, block)
+			}
+		}
+		statuses = append(statuses, status)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses, nil
+}
+
+// indexStatusFromRecord, _cat/indices satırını IndexStatus'a çevirir
+// _cat API'si tüm değerleri string döner; kapalı indekslerde sayısal alanlar boştur
+func indexStatusFromRecord(record types.IndicesRecord) IndexStatus {
+	status := IndexStatus{
+		Name:      stringValue(record.Index),
+		Health:    stringValue(record.Health),
+		State:     stringValue(record.Status),
+		StoreSize: stringValue(record.StoreSize),
+	}
+	status.DocsCount, _ = strconv.ParseInt(stringValue(record.DocsCount), 10, 64)
+	status.SegmentsCount, _ = strconv.ParseInt(stringValue(record.SegmentsCount), 10, 64)
+	status.PrimaryShards, _ = strconv.Atoi(stringValue(record.Pri))
+	status.Replicas, _ = strconv.Atoi(stringValue(record.Rep))
+	return status
+}
+
+// flatSettings, indekslerin ayarlarını indeks adı -> "index.refresh_interval" gibi düz anahtarlar olarak döner
+// Tipli GetSettings yanıtı flat_settings ile çözülemediği için ham yanıt okunur
+func flatSettings(client *elasticsearch.TypedClient, index string) (map[string]map[string]string, error) {
+	res, err := client.Indices.GetSettings().Index(index).FlatSettings(true).Perform(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode >= 300 {
+		errorResponse := types.NewElasticsearchError()
+		if err := json.Unmarshal(body, errorResponse); err != nil {
+			return nil, fmt.Errorf("ayarlar okunamadı (HTTP %d): %s", res.StatusCode, body)
+		}
+		if errorResponse.Status == 0 {
+			errorResponse.Status = res.StatusCode
+		}
+		return nil, errorResponse
+	}
+
+	var raw map[string]struct {
+		Settings map[string]interface{} `json:"settings"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, err
+	}
+
+	settings := make(map[string]map[string]string, len(raw))
+	for name, state := range raw {
+		values := make(map[string]string, len(state.Settings))
+		for key, value := range state.Settings {
+			values[key] = fmt.Sprint(value)
+		}
+		settings[name] = values
+	}
+	return settings, nil
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
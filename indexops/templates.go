@@ -0,0 +1,314 @@
+package indexops
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/cluster/putcomponenttemplate"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/indices/putindextemplate"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+)
+
+/*
+	Index ve Component Template'ler:
+	Component template'ler ortak parçalardır (örn: shard/replica ayarları, Türkçe analyzer'lar, ürün mapping'i)
+	Index template, index_patterns ile eşleşen her yeni indekse (products-2026.10, app-logs-2026.10) bu parçaları
+	composed_of sırasıyla uygular; indeks ilk yazmada otomatik oluşturulsa bile doğru mapping'i alır
+
+	Diff/Apply:
+	Elasticsearch template'leri kaydederken ayarları normalize eder (örn: "number_of_shards": "1" ->
+	"index": {"number_of_shards": "1"}), bu yüzden içerik alan alan karşılaştırılmaz. Her template'in
+	_meta.checksum alanına Go tanımının özeti yazılır; Diff bu özeti ve version'ı karşılaştırır
+*/
+
+// checksumMetaKey, template'in _meta alanında tanımın özetinin tutulduğu anahtardır
+const checksumMetaKey = "checksum"
+
+// TemplateAction, Diff sonucunda bir template için yapılacak işlemdir
+type TemplateAction string
+
+const (
+	TemplateCreate    TemplateAction = "create"    // kümede yok
+	TemplateUpdate    TemplateAction = "update"    // kümedeki tanım farklı
+	TemplateUnchanged TemplateAction = "unchanged" // kümedeki tanım aynı
+)
+
+// ComponentTemplate, index template'lerin birleştirdiği ortak parçadır
+type ComponentTemplate struct {
+	Name     string
+	Version  int64
+	Settings *types.IndexSettings
+	Mappings *types.TypeMapping
+}
+
+// IndexTemplate, index_patterns ile eşleşen yeni indekslere uygulanan composable template'dir
+type IndexTemplate struct {
+	Name          string
+	Version       int64
+	IndexPatterns []string
+	ComposedOf    []string // component template adları; sonraki, öncekinin ayarlarını ezer
+	Priority      int64    // aynı indekse uyan template'lerden önceliği yüksek olan uygulanır
+	DataStream    bool     // eşleşen adlar indeks yerine data stream olarak oluşturulur
+
+	// Template, component'lerden sonra uygulanan ve sadece bu template'e özel ayar/mapping/alias'lardır
+	Template *types.IndexTemplateMapping
+}
+
+// TemplateSet, birlikte yönetilen ingest pipeline'lar, ILM politikaları, component ve index template'leridir
+type TemplateSet struct {
+	Pipelines  []Pipeline
+	Policies   []LifecyclePolicy
+	Components []ComponentTemplate
+	Indices    []IndexTemplate
+}
+
+// TemplateChange, bir template'in kümedeki hali ile Go tanımı arasındaki farktır
+type TemplateChange struct {
+	Kind           string // "pipeline", "policy", "component" ya da "index"
+	Name           string
+	Action         TemplateAction
+	CurrentVersion *int64 // kümede yoksa nil
+	DesiredVersion int64
+}
+
+func (c TemplateChange) String() string {
+	current := "-"
+	if c.CurrentVersion != nil {
+		current = fmt.Sprint(*c.CurrentVersion)
+	}
+	return fmt.Sprintf("%-9s %-9s %s (v%s -> v%d)", c.Action, c.Kind, c.Name, current, c.DesiredVersion)
+}
+
+// Diff, kümedeki pipeline, politika ve template'leri Go tanımlarıyla karşılaştırır; kümede değişiklik yapmaz
+func (s TemplateSet) Diff(client *elasticsearch.TypedClient) ([]TemplateChange, error) {
+	changes := make([]TemplateChange, 0, len(s.Pipelines)+len(s.Policies)+len(s.Components)+len(s.Indices))
+
+	for _, pipeline := range s.Pipelines {
+		request, err := pipeline.request()
+		if err != nil {
+			return nil, err
+		}
+		current, err := pipelineState(client, pipeline.Name)
+		if err != nil {
+			return nil, fmt.Errorf("%s pipeline'ı okunamadı: %w", pipeline.Name, err)
+		}
+		changes = append(changes, templateChange("pipeline", pipeline.Name, pipeline.Version, request.Meta_, current))
+	}
+
+	for _, policy := range s.Policies {
+		request, err := policy.request()
+		if err != nil {
+			return nil, err
+		}
+		current, err := policyState(client, policy.Name)
+		if err != nil {
+			return nil, fmt.Errorf("%s ILM politikası okunamadı: %w", policy.Name, err)
+		}
+		changes = append(changes, templateChange("policy", policy.Name, policy.Version, request.Policy.Meta_, current))
+	}
+
+	for _, component := range s.Components {
+		request, err := component.request()
+		if err != nil {
+			return nil, err
+		}
+		current, err := templateState(client.Cluster.GetComponentTemplate().Name(component.Name).Perform, "component_templates", "component_template")
+		if err != nil {
+			return nil, fmt.Errorf("%s component template'i okunamadı: %w", component.Name, err)
+		}
+		changes = append(changes, templateChange("component", component.Name, component.Version, request.Meta_, current))
+	}
+
+	for _, index := range s.Indices {
+		request, err := index.request()
+		if err != nil {
+			return nil, err
+		}
+		current, err := templateState(client.Indices.GetIndexTemplate().Name(index.Name).Perform, "index_templates", "index_template")
+		if err != nil {
+			return nil, fmt.Errorf("%s index template'i okunamadı: %w", index.Name, err)
+		}
+		changes = append(changes, templateChange("index", index.Name, index.Version, request.Meta_, current))
+	}
+
+	return changes, nil
+}
+
+// Apply, farklı ya da eksik pipeline, politika ve template'leri kümeye yazar ve karşılaştırma sonucunu döner
+// Template'ler pipeline ve politikalara, index template'ler component'lere başvurduğu için bu sırayla yazılır
+func (s TemplateSet) Apply(client *elasticsearch.TypedClient) ([]TemplateChange, error) {
+	ctx := context.Background()
+
+	changes, err := s.Diff(client)
+	if err != nil {
+		return nil, err
+	}
+
+	// changes, Diff'teki sırayla pipeline'ları, politikaları, component'leri ve index template'leri içerir
+	pipelines := changes[:len(s.Pipelines)]
+	policies := changes[len(pipelines) : len(pipelines)+len(s.Policies)]
+	components := changes[len(pipelines)+len(policies) : len(pipelines)+len(policies)+len(s.Components)]
+	indices := changes[len(pipelines)+len(policies)+len(components):]
+
+	for i, change := range pipelines {
+		if change.Action == TemplateUnchanged {
+			continue
+		}
+		request, err := s.Pipelines[i].request()
+		if err != nil {
+			return changes, err
+		}
+		if _, err := putPipeline(client, change.Name, request); err != nil {
+			return changes, fmt.Errorf("%s pipeline'ı yazılamadı: %w", change.Name, err)
+		}
+	}
+
+	for i, change := range policies {
+		if change.Action == TemplateUnchanged {
+			continue
+		}
+		request, err := s.Policies[i].request()
+		if err != nil {
+			return changes, err
+		}
+		if _, err := client.Ilm.PutLifecycle(change.Name).Request(request).Do(ctx); err != nil {
+			return changes, fmt.Errorf("%s ILM politikası yazılamadı: %w", change.Name, err)
+		}
+	}
+
+	for i, change := range components {
+		if change.Action == TemplateUnchanged {
+			continue
+		}
+		request, err := s.Components[i].request()
+		if err != nil {
+			return changes, err
+		}
+		if _, err := client.Cluster.PutComponentTemplate(change.Name).Request(request).Do(ctx); err != nil {
+			return changes, fmt.Errorf("%s component template'i yazılamadı: %w", change.Name, err)
+		}
+	}
+
+	for i, change := range indices {
+		if change.Action == TemplateUnchanged {
+			continue
+		}
+		request, err := s.Indices[i].request()
+		if err != nil {
+			return changes, err
+		}
+		if _, err := client.Indices.PutIndexTemplate(change.Name).Request(request).Do(ctx); err != nil {
+			return changes, fmt.Errorf("%s index template'i yazılamadı: %w", change.Name, err)
+		}
+	}
+	return changes, nil
+}
+
+func (c ComponentTemplate) request() (*putcomponenttemplate.Request, error) {
+	request := &putcomponenttemplate.Request{
+		Template: types.IndexState{Settings: c.Settings, Mappings: c.Mappings},
+		Version:  &c.Version,
+	}
+	meta, err := checksumMeta(request)
+	if err != nil {
+		return nil, err
+	}
+	request.Meta_ = meta
+	return request, nil
+}
+
+func (t IndexTemplate) request() (*putindextemplate.Request, error) {
+	request := &putindextemplate.Request{
+		IndexPatterns: t.IndexPatterns,
+		ComposedOf:    t.ComposedOf,
+		Priority:      &t.Priority,
+		Version:       &t.Version,
+		Template:      t.Template,
+	}
+	if t.DataStream {
+		request.DataStream = &types.DataStreamVisibility{}
+	}
+	meta, err := checksumMeta(request)
+	if err != nil {
+		return nil, err
+	}
+	request.Meta_ = meta
+	return request, nil
+}
+
+// checksumMeta, _meta alanı boşken istek gövdesinin özetini içeren _meta üretir
+func checksumMeta(request interface{}) (types.Metadata, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(body)
+	checksum, err := json.Marshal(hex.EncodeToString(sum[:8]))
+	if err != nil {
+		return nil, err
+	}
+	return types.Metadata{checksumMetaKey: checksum}, nil
+}
+
+// storedTemplate, kümedeki bir template'in karşılaştırmada kullanılan alanlarıdır
+type storedTemplate struct {
+	Version *int64         `json:"version"`
+	Meta    types.Metadata `json:"_meta"`
+}
+
+// templateState, GET template yanıtından version ve _meta'yı okur; template yoksa nil döner
+// Tipli yanıt, tüm analyzer ve mapping union'larını çözmeye çalıştığı için ham yanıt okunur
+func templateState(perform func(context.Context) (*http.Response, error), listKey, itemKey string) (*storedTemplate, error) {
+	res, err := perform(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode >= 300 {
+		return nil, fmt.Errorf("HTTP %d: %s", res.StatusCode, body)
+	}
+
+	var raw map[string][]map[string]json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, err
+	}
+	items := raw[listKey]
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	var stored storedTemplate
+	if err := json.Unmarshal(items[0][itemKey], &stored); err != nil {
+		return nil, err
+	}
+	return &stored, nil
+}
+
+func templateChange(kind, name string, version int64, desired types.Metadata, current *storedTemplate) TemplateChange {
+	change := TemplateChange{Kind: kind, Name: name, DesiredVersion: version, Action: TemplateCreate}
+	if current == nil {
+		return change
+	}
+
+	change.CurrentVersion = current.Version
+	change.Action = TemplateUpdate
+	if current.Version != nil && *current.Version == version &&
+		string(current.Meta[checksumMetaKey]) == string(desired[checksumMetaKey]) {
+		change.Action = TemplateUnchanged
+	}
+	return change
+}
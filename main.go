@@ -4,41 +4,97 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"os"
 	"time"
 
+	"github.com/SadikSunbul/Go-Elasticsearch/breaker"
+	"github.com/SadikSunbul/Go-Elasticsearch/export"
+	"github.com/SadikSunbul/Go-Elasticsearch/q"
+	"github.com/SadikSunbul/Go-Elasticsearch/retry"
 	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/indices/create"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
 )
 
 // Ürün yapısı
+// .keyword alt alanları değeri olduğu gibi saklar; aggregation'lar ve raporlar "Ayakkabı" gibi asıl yazımı gösterir
+// name.norm ve category.norm turkish_keyword normalizer'ı ile saklanır: "AYAKKABI", "Ayakkabı" ve "ayakkabi"
+// bu alanlarda aynı terimle eşleşir
+// Varsayılan highlight alanları (name, brand, category) fvh highlighter'ı için term vector'leriyle saklanır
 type Product struct {
-	ID          string    `json:"id"`
-	Name        string    `json:"name"`
-	Brand       string    `json:"brand"`
-	Category    string    `json:"category"`
+	ID          string    `json:"id" es:"keyword"`
+	Name        string    `json:"name" es:"text,analyzer=turkish_text,keyword,norm=turkish_keyword,fields=folded:turkish_folded|suggest:turkish_plain,term_vector=with_positions_offsets"`
+	Brand       string    `json:"brand" es:"text,keyword,term_vector=with_positions_offsets"`
+	Category    string    `json:"category" es:"text,analyzer=turkish_text,keyword,norm=turkish_keyword,fields=folded:turkish_folded|suggest:turkish_plain,term_vector=with_positions_offsets"`
 	Price       float64   `json:"price"`
-	Color       string    `json:"color"`
-	Size        string    `json:"size"`
+	Color       string    `json:"color" es:"keyword"`
+	Size        string    `json:"size" es:"keyword"`
 	Rating      float64   `json:"rating"`
-	StockCount  int       `json:"stock_count"`
-	SoldCount   int       `json:"sold_count"`
+	StockCount  int       `json:"stock_count" es:"integer"`
+	SoldCount   int       `json:"sold_count" es:"integer"`
 	CreateDate  time.Time `json:"create_date"`
 	IsAvailable bool      `json:"is_available"`
+
+	// products-clean pipeline'ının yazdığı alanlar; Go tarafından gönderilmez
+	IngestedAt  *time.Time `json:"ingested_at,omitempty"`
+	IngestError string     `json:"ingest_error,omitempty" es:"keyword"`
 }
 
+// clusterBreaker, aynı kümeye bağlanan low-level ve typed istemcilerin bilerek paylaştığı breaker'dır
+var clusterBreaker = breaker.New(breaker.DefaultThreshold, breaker.DefaultCooldown)
+
 // Elasticsearch bağlantısını oluşturan fonksiyon
 func createESClient() (*elasticsearch.Client, error) {
 	cfg := elasticsearch.Config{
 		Addresses: []string{"http://localhost:9200"},
 	}
-	client, err := elasticsearch.NewClient(cfg)
+	client, err := elasticsearch.NewClient(breaker.ConfigureWith(retry.Configure(cfg), clusterBreaker))
 	if err != nil {
 		return nil, err
 	}
 	return client, nil
 }
 
+// Typed Elasticsearch bağlantısını oluşturan fonksiyon
+func createTypedESClient() (*elasticsearch.TypedClient, error) {
+	return elasticsearch.NewTypedClient(breaker.ConfigureWith(retry.Configure(elasticsearch.Config{
+		Addresses: []string{"http://localhost:9200"},
+	}), clusterBreaker))
+}
+
+// products-v1 indeksini Product struct'ından üretilen mapping ile oluşturup products alias'ını ona bağlayan fonksiyon
+// Servisler fiziksel indeks yerine products alias'ını kullanır; yeni sürüm "aliases swap" ile devreye alınır
+func createProductIndex(client *elasticsearch.TypedClient) error {
+	ctx := context.Background()
+
+	// products bir alias ya da (eski kurulumlarda) fiziksel bir indeks olabilir
+	exists, err := client.Indices.Exists(productsAlias).Do(ctx)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	mapping, err := MappingFromStruct(Product{})
+	if err != nil {
+		return err
+	}
+
+	isWriteIndex := true
+	_, err = client.Indices.Create(ProductsIndex(1)).
+		Request(&create.Request{
+			Mappings: mapping,
+			Settings: ProductIndexSettings(),
+			Aliases:  map[string]types.Alias{productsAlias: {IsWriteIndex: &isWriteIndex}},
+		}).
+		Do(ctx)
+	return err
+}
+
 // Örnek ürün verilerini ekleyen fonksiyon
 func addSampleProducts(client *elasticsearch.Client) error {
 	ctx := context.Background()
@@ -78,33 +134,12 @@ func addSampleProducts(client *elasticsearch.Client) error {
 	return nil
 }
 
-// Fiyat aralığına ve kategoriye göre arama yapan fonksiyon
-func searchByPriceAndCategory(client *elasticsearch.Client, minPrice, maxPrice float64, category string) ([]Product, error) {
+// Türkçe metin alanında arama yapan fonksiyon ("ayakkabi" sorgusu "Ayakkabı" ürünlerini de bulur)
+func searchByText(client *elasticsearch.Client, field, text string) ([]Product, error) {
 	ctx := context.Background()
 
 	query := map[string]interface{}{
-		"query": map[string]interface{}{
-			"bool": map[string]interface{}{
-				"must": []map[string]interface{}{
-					{
-						"range": map[string]interface{}{
-							"price": map[string]interface{}{
-								"gte": minPrice,
-								"lte": maxPrice,
-							},
-						},
-					},
-					{
-						"match": map[string]interface{}{
-							"category": category,
-						},
-					},
-				},
-			},
-		},
-		"sort": []map[string]interface{}{
-			{"price": "asc"},
-		},
+		"query": turkishTextQuery(field, text),
 	}
 
 	body, err := json.Marshal(query)
@@ -141,170 +176,232 @@ func searchByPriceAndCategory(client *elasticsearch.Client, minPrice, maxPrice f
 	return products, nil
 }
 
-// Çok kriterli gelişmiş arama fonksiyonu
-func advancedSearch(client *elasticsearch.Client, params map[string]interface{}) ([]Product, error) {
-	ctx := context.Background()
-
-	must := []map[string]interface{}{}
+func main() {
+	// Elasticsearch client oluştur
+	client, err := createESClient()
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	if brand, ok := params["brand"].(string); ok {
-		must = append(must, map[string]interface{}{
-			"match": map[string]interface{}{
-				"brand": brand,
-			},
-		})
+	typedClient, err := createTypedESClient()
+	if err != nil {
+		log.Fatal(err)
 	}
 
-	if minRating, ok := params["min_rating"].(float64); ok {
-		must = append(must, map[string]interface{}{
-			"range": map[string]interface{}{
-				"rating": map[string]interface{}{
-					"gte": minRating,
-				},
-			},
-		})
+	// go run . templates diff|apply: index ve component template'lerini karşılaştır ya da uygula
+	// go run . aliases list|swap|tenants|remove: products alias'larını yönet
+	// go run . snapshots backup|list|restore|delete: products indekslerini yedekle ve geri yükle
+	// go run . pipelines simulate|attach: ürün ingest pipeline'ını dene ya da bir indekse bağla
+	// go run . dlq list|replay: yükleyicilerin indeksleyemediği belgeleri listele ya da tekrar gönder
+	if len(os.Args) > 1 {
+		commands := map[string]func(*elasticsearch.TypedClient, []string) error{
+			"templates": runTemplatesCommand,
+			"aliases":   runAliasesCommand,
+			"snapshots": runSnapshotsCommand,
+			"pipelines": runPipelinesCommand,
+			"dlq":       runDLQCommand,
+		}
+		if command, ok := commands[os.Args[1]]; ok {
+			if err := command(typedClient, os.Args[2:]); err != nil {
+				// Küme yük altındaysa komut hemen başarısız olur; breaker'ın durumu ve son hatası da yazılır
+				if errors.Is(err, breaker.ErrOpen) {
+					log.Printf("Circuit breaker: %s", clusterBreaker.Snapshot())
+				}
+				log.Fatal(err)
+			}
+			return
+		}
 	}
 
-	if inStock, ok := params["in_stock"].(bool); ok && inStock {
-		must = append(must, map[string]interface{}{
-			"range": map[string]interface{}{
-				"stock_count": map[string]interface{}{
-					"gt": 0,
-				},
-			},
-		})
+	// products indeksini struct tag'lerinden üretilen mapping ile oluştur
+	if err := createProductIndex(typedClient); err != nil {
+		log.Fatal(err)
 	}
 
-	query := map[string]interface{}{
-		"query": map[string]interface{}{
-			"bool": map[string]interface{}{
-				"must": must,
-			},
-		},
-		"sort": []map[string]interface{}{
-			{"rating": "desc"},
-		},
-		"size": 20,
+	// Açılışta indeks mapping'inin Product struct'ıyla uyumlu olduğunu kontrol et
+	if err := ValidateStructMapping(typedClient, "products", Product{}); err != nil {
+		log.Fatal(err)
 	}
 
-	body, err := json.Marshal(query)
+	// Örnek ürünleri ekle
+	err = addSampleProducts(client)
 	if err != nil {
-		return nil, err
+		log.Fatal(err)
 	}
 
-	res, err := client.Search(
-		client.Search.WithContext(ctx),
-		client.Search.WithIndex("products"),
-		client.Search.WithBody(bytes.NewReader(body)),
-	)
+	// Türkçe karakter yazılmadan arama örneği
+	textResults, err := searchByText(client, "category", "ayakkabi")
 	if err != nil {
-		return nil, err
+		log.Fatal(err)
 	}
-	defer res.Body.Close()
-
-	var result map[string]interface{}
-	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
-		return nil, err
+	fmt.Printf("Metin arama sonuçları: %+v\n", textResults)
+
+	// Serbest metin ürün arama örneği
+	searchResult, err := SearchProducts(typedClient, ProductQuery{
+		Text: "nike air max",
+		Highlight: &HighlightOptions{
+			FragmentSize:      100,
+			NumberOfFragments: 2,
+			PreTags:           []string{"<mark>"},
+			PostTags:          []string{"</mark>"},
+		},
+	})
+	if err != nil {
+		log.Fatal(err)
 	}
-
-	hits := result["hits"].(map[string]interface{})["hits"].([]interface{})
-	var products []Product
-	for _, hit := range hits {
-		source := hit.(map[string]interface{})["_source"].(map[string]interface{})
-		var product Product
-		jsonData, _ := json.Marshal(source)
-		if err := json.Unmarshal(jsonData, &product); err != nil {
-			return nil, err
-		}
-		products = append(products, product)
+	fmt.Printf("Ürün arama sonuçları (%d):\n", searchResult.Total)
+	for _, hit := range searchResult.Hits {
+		fmt.Printf("ID: %s, Score: %f, Ürün: %s, Vurgular: %v\n", hit.ID, hit.Score, hit.Product.Name, hit.Highlight)
 	}
-	return products, nil
-}
 
-// En çok satanları getiren fonksiyon
-func getMostSoldProducts(client *elasticsearch.Client, limit int) ([]Product, error) {
-	ctx := context.Background()
-
-	query := map[string]interface{}{
-		"query": map[string]interface{}{
-			"match_all": map[string]interface{}{},
-		},
-		"sort": []map[string]interface{}{
-			{"sold_count": "desc"},
+	// Hatalı yazılmış sorgu için "bunu mu demek istediniz?" önerileri
+	typoResult, err := SearchProducts(typedClient, ProductQuery{
+		Text: "ayakabı",
+		Suggest: &SuggestOptions{
+			Field:   "category." + SuggestSubField,
+			Term:    true,
+			Phrase:  true,
+			Collate: true,
 		},
-		"size": limit,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if typoResult.Total == 0 && len(typoResult.Suggestions) > 0 {
+		fmt.Printf("Bunu mu demek istediniz: %s?\n", typoResult.Suggestions[0].Text)
 	}
 
-	body, err := json.Marshal(query)
+	// Kayıtlı search template ile arama örneği
+	if err := RegisterSearchTemplates(typedClient, ProductSearchTemplates...); err != nil {
+		log.Fatal(err)
+	}
+	templateParams := ProductsByPriceCategoryParams{MinPrice: 1000, MaxPrice: 2000, Category: "Ayakkabı"}
+	rendered, err := RenderSearchTemplate(typedClient, "products_by_price_category", templateParams)
 	if err != nil {
-		return nil, err
+		log.Fatal(err)
 	}
-
-	res, err := client.Search(
-		client.Search.WithContext(ctx),
-		client.Search.WithIndex("products"),
-		client.Search.WithBody(bytes.NewReader(body)),
-	)
+	fmt.Printf("Derlenen şablon: %s\n", rendered)
+	templateResult, err := SearchTemplate(typedClient, "products_by_price_category", templateParams)
 	if err != nil {
-		return nil, err
+		log.Fatal(err)
 	}
-	defer res.Body.Close()
+	fmt.Printf("Şablon arama sonuçları (%d): %+v\n", templateResult.Total, templateResult.Hits)
 
-	var result map[string]interface{}
-	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
-		return nil, err
+	// Ürün sayfasının üç sorgusunu (fiyat ve kategori, gelişmiş arama, en çok satanlar) tek _msearch isteğinde çalıştır
+	priceRequest, err := PriceAndCategoryRequest(1000, 2000, "Ayakkabı")
+	if err != nil {
+		log.Fatal(err)
 	}
-
-	hits := result["hits"].(map[string]interface{})["hits"].([]interface{})
-	var products []Product
-	for _, hit := range hits {
-		source := hit.(map[string]interface{})["_source"].(map[string]interface{})
-		var product Product
-		jsonData, _ := json.Marshal(source)
-		if err := json.Unmarshal(jsonData, &product); err != nil {
-			return nil, err
+	advancedRequest, err := AdvancedSearchRequest(AdvancedSearchParams{Brand: "Nike", MinRating: 4.0, InStock: true})
+	if err != nil {
+		log.Fatal(err)
+	}
+	pageQueries := []string{"Fiyat ve kategori", "Gelişmiş arama", "En çok satanlar"}
+	pageResults, err := MultiSearchProducts(typedClient, priceRequest, advancedRequest, MostSoldRequest(10))
+	if err != nil {
+		log.Fatal(err)
+	}
+	for i, pageResult := range pageResults {
+		if pageResult.Err != nil {
+			fmt.Printf("%s sorgusu hatası: %v\n", pageQueries[i], pageResult.Err)
+			continue
 		}
-		products = append(products, product)
+		fmt.Printf("%s sonuçları (%d): %+v\n", pageQueries[i], pageResult.Result.Total, pageResult.Result.Hits)
 	}
-	return products, nil
-}
 
-func main() {
-	// Elasticsearch client oluştur
-	client, err := createESClient()
+	// Sayma örneği: stoktaki Nike ürünleri, indeks bazında dağılımıyla
+	countResult, err := CountDocuments(typedClient, CountRequest{
+		Indices: []string{"products*"},
+		Query:   q.Bool().Filter(q.Term("brand.keyword", "Nike"), q.Range("stock_count").Gt(0)),
+		ByIndex: true,
+	})
 	if err != nil {
 		log.Fatal(err)
 	}
+	fmt.Printf("Stoktaki Nike ürünleri: %d (kesin: %t), indeks bazında: %v\n", countResult.Count, countResult.Exact, countResult.ByIndex)
 
-	// Örnek ürünleri ekle
-	err = addSampleProducts(client)
+	hasOutOfStock, err := DocumentExists(typedClient, q.Term("stock_count", 0), "products")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Stokta olmayan ürün var mı: %t\n", hasOutOfStock)
+
+	// Tarih histogramı örneği: son 7 günde her gün eklenen ürün sayısı (İstanbul saatine göre)
+	dailyProducts, err := ProductsAddedPerDay(typedClient, 7, "Europe/Istanbul")
 	if err != nil {
 		log.Fatal(err)
 	}
+	for _, day := range dailyProducts {
+		fmt.Printf("%s: %d ürün eklendi\n", day.Key, day.Count)
+	}
 
-	// Fiyat ve kategoriye göre arama örneği
-	products, err := searchByPriceAndCategory(client, 1000, 2000, "Ayakkabı")
+	// Aylık histogram, sadece Nike ürünleri ve sadece ürün eklenen aylar
+	monthlyNike, err := DateHistogram(typedClient, DateHistogramRequest{
+		Query:            q.Term("brand.keyword", "Nike"),
+		From:             "now-1y/M",
+		To:               "now/M",
+		CalendarInterval: "month",
+		TimeZone:         "+03:00",
+		Format:           "yyyy-MM",
+		SkipEmpty:        true,
+	})
 	if err != nil {
 		log.Fatal(err)
 	}
-	fmt.Printf("Bulunan ürünler: %+v\n", products)
+	fmt.Printf("Aylık eklenen Nike ürünleri: %+v\n", monthlyNike)
 
-	// Gelişmiş arama örneği
-	searchParams := map[string]interface{}{
-		"brand":      "Nike",
-		"min_rating": 4.0,
-		"in_stock":   true,
+	// Satış analizi örneği
+	report, err := ProductSalesAnalytics(typedClient, SalesAnalyticsOptions{TopBrands: 5})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Toplam tahmini ciro: %.2f, marka sayısı: %d, stokta olmayan: %d\n", report.TotalRevenue, report.BrandCount, report.OutOfStock)
+	for _, brand := range report.TopBrands {
+		fmt.Printf("Marka %s: %d satış, %.2f ciro\n", brand.Brand, brand.SoldCount, brand.Revenue)
 	}
-	advancedResults, err := advancedSearch(client, searchParams)
+	for _, category := range report.Categories {
+		fmt.Printf("Kategori %s: ortalama puan %.2f, fiyat yüzdelikleri %+v\n", category.Category, category.AvgRating, category.PricePercentiles)
+	}
+
+	// Composite aggregation örneği: tüm marka × kategori kombinasyonlarını CSV dosyasına yaz
+	csvFile, err := os.Create("brand_category.csv")
 	if err != nil {
 		log.Fatal(err)
 	}
-	fmt.Printf("Gelişmiş arama sonuçları: %+v\n", advancedResults)
+	if err := BrandCategoryPager(typedClient, nil).WriteCSV(csvFile); err != nil {
+		csvFile.Close()
+		log.Fatal(err)
+	}
+	if err := csvFile.Close(); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("Marka × kategori raporu brand_category.csv dosyasına yazıldı")
 
-	// En çok satanları getir
-	mostSold, err := getMostSoldProducts(client, 10)
+	// Dışa aktarma örneği: gelişmiş aramanın tüm sonuçlarını Excel'de açılabilecek TSV dosyasına yaz
+	exportFile, err := os.Create("nike_products.tsv")
+	if err != nil {
+		log.Fatal(err)
+	}
+	exported, err := export.Export(typedClient, exportFile, export.Options{
+		Index: "products",
+		Query: q.Raw(advancedRequest.Query),
+		Columns: []export.Column{
+			{Field: export.IDField, Header: "ID"},
+			{Field: "name", Header: "Ürün"},
+			{Field: "category", Header: "Kategori"},
+			{Field: "price", Header: "Fiyat"},
+			{Field: "stock_count", Header: "Stok"},
+		},
+		Format:   export.TSV,
+		Numbers:  export.TurkishNumbers,
+		ExcelBOM: true,
+	})
 	if err != nil {
+		exportFile.Close()
+		log.Fatal(err)
+	}
+	if err := exportFile.Close(); err != nil {
 		log.Fatal(err)
 	}
-	fmt.Printf("En çok satanlar: %+v\n", mostSold)
+	fmt.Printf("%d ürün nike_products.tsv dosyasına aktarıldı\n", exported)
 }
@@ -0,0 +1,344 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/termvectoroption"
+)
+
+/*
+	Struct Tag ile Mapping Üretimi:
+	Go struct'larındaki `es:"..."` tag'lerinden types.TypeMapping oluşturur
+	İlk değer alan tipidir, sonrakiler seçeneklerdir:
+		`es:"keyword"`
+		`es:"text,analyzer=turkish"`
+		`es:"text,keyword"`          -> text + .keyword alt alanı (multi-field)
+		`es:"text,norm=turkish_keyword"` -> verilen normalizer ile .norm keyword alt alanı
+		`es:"text,fields=folded:turkish_folded"` -> verilen analyzer ile text alt alanları (| ile ayrılır)
+		`es:"text,term_vector=with_positions_offsets"` -> fvh highlighter için term vector
+		`es:"nested"`
+		`es:"geo_point"`
+		`es:"date,format=yyyy-MM-dd"`
+		`es:"-"`                     -> alan mapping'e eklenmez
+	Tag yoksa tip Go tipinden çıkarılır (time.Time -> date, bool -> boolean, float64 -> double ...)
+	Alan adı json tag'inden alınır
+*/
+
+// keywordIgnoreAbove, .keyword alt alanları için Elasticsearch'ün dinamik mapping varsayılanıdır
+const keywordIgnoreAbove = 256
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// esTag, ayrıştırılmış `es` struct tag'ini temsil eder
+type esTag struct {
+	Type    string
+	Options map[string]string
+}
+
+// has, seçeneğin (değerli ya da değersiz) tag'de olup olmadığını döner
+func (t esTag) has(name string) bool {
+	_, ok := t.Options[name]
+	return ok
+}
+
+// parseESTag, `es:"text,analyzer=turkish,keyword"` biçimindeki tag'i ayrıştırır
+func parseESTag(tag string) esTag {
+	parsed := esTag{Options: map[string]string{}}
+	if tag == "" {
+		return parsed
+	}
+
+	parts := strings.Split(tag, ",")
+	parsed.Type = strings.TrimSpace(parts[0])
+	for _, part := range parts[1:] {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(part, "=")
+		parsed.Options[key] = value
+	}
+	return parsed
+}
+
+// jsonFieldName, alanın JSON'daki adını döner; alan atlanacaksa boş string döner
+func jsonFieldName(field reflect.StructField) string {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// MappingFromStruct, verilen struct'ın `es` ve `json` tag'lerinden bir TypeMapping üretir
+func MappingFromStruct(v interface{}) (*types.TypeMapping, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("mapping için struct bekleniyordu, gelen: %v", t)
+	}
+
+	properties, err := propertiesFromStruct(t)
+	if err != nil {
+		return nil, err
+	}
+	return &types.TypeMapping{Properties: properties}, nil
+}
+
+// propertiesFromStruct, struct alanlarını mapping property'lerine dönüştürür
+func propertiesFromStruct(t reflect.Type) (map[string]types.Property, error) {
+	properties := map[string]types.Property{}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		// Gömülü (embedded) struct alanları üst seviyeye açılır
+		if field.Anonymous && field.Tag.Get("json") == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				inner, err := propertiesFromStruct(embedded)
+				if err != nil {
+					return nil, err
+				}
+				for name, property := range inner {
+					properties[name] = property
+				}
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		rawTag := field.Tag.Get("es")
+		if rawTag == "-" {
+			continue
+		}
+
+		name := jsonFieldName(field)
+		if name == "" {
+			continue
+		}
+
+		property, err := propertyFor(field.Type, parseESTag(rawTag))
+		if err != nil {
+			return nil, fmt.Errorf("%s alanı: %w", name, err)
+		}
+		properties[name] = property
+	}
+	return properties, nil
+}
+
+// propertyFor, tag'de tip verilmişse onu, verilmemişse Go tipinden çıkarılan tipi kullanır
+func propertyFor(t reflect.Type, tag esTag) (types.Property, error) {
+	// Pointer ve slice alanlarında eleman tipine bakılır, Elasticsearch'te dizi ayrı bir tip değildir
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		if t.Elem().Kind() == reflect.Uint8 && t.Kind() == reflect.Slice {
+			break
+		}
+		t = t.Elem()
+	}
+
+	fieldType := tag.Type
+	if fieldType == "" {
+		fieldType = defaultFieldType(t)
+		if fieldType == "" {
+			return nil, fmt.Errorf("%s tipi için varsayılan mapping yok, `es` tag'i ekleyin", t)
+		}
+	}
+
+	switch fieldType {
+	case "text":
+		p := types.NewTextProperty()
+		p.Analyzer = optionString(tag, "analyzer")
+		p.SearchAnalyzer = optionString(tag, "search_analyzer")
+		// Tag'siz string alanlar, dinamik mapping gibi text + .keyword olarak eşlenir
+		if tag.has("keyword") || tag.Type == "" {
+			p.Fields["keyword"] = keywordSubField("")
+		}
+		// .keyword aggregation ve gösterim için değeri olduğu gibi saklar; .norm büyük/küçük harf ve aksan duyarsız eşleşme içindir
+		if normalizer, ok := tag.Options[NormalizedSubField]; ok {
+			if normalizer == "" {
+				return nil, fmt.Errorf("%s seçeneği için normalizer adı verilmeli", NormalizedSubField)
+			}
+			p.Fields[NormalizedSubField] = keywordSubField(normalizer)
+		}
+		if err := applyTextSubFields(tag, p.Fields); err != nil {
+			return nil, err
+		}
+		if value, ok := tag.Options["term_vector"]; ok {
+			p.TermVector = &termvectoroption.TermVectorOption{Name: value}
+		}
+		if err := applyIndexOption(tag, &p.Index); err != nil {
+			return nil, err
+		}
+		return p, nil
+	case "keyword":
+		p := types.NewKeywordProperty()
+		p.Normalizer = optionString(tag, "normalizer")
+		if value, ok := tag.Options["ignore_above"]; ok {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("geçersiz ignore_above değeri %q: %w", value, err)
+			}
+			p.IgnoreAbove = &n
+		}
+		if err := applyIndexOption(tag, &p.Index); err != nil {
+			return nil, err
+		}
+		return p, nil
+	case "date":
+		p := types.NewDateProperty()
+		p.Format = optionString(tag, "format")
+		return p, nil
+	case "boolean":
+		return types.NewBooleanProperty(), nil
+	case "byte":
+		return types.NewByteNumberProperty(), nil
+	case "short":
+		return types.NewShortNumberProperty(), nil
+	case "integer":
+		return types.NewIntegerNumberProperty(), nil
+	case "long":
+		return types.NewLongNumberProperty(), nil
+	case "unsigned_long":
+		return types.NewUnsignedLongNumberProperty(), nil
+	case "float":
+		return types.NewFloatNumberProperty(), nil
+	case "double":
+		return types.NewDoubleNumberProperty(), nil
+	case "binary":
+		return types.NewBinaryProperty(), nil
+	case "geo_point":
+		return types.NewGeoPointProperty(), nil
+	case "geo_shape":
+		return types.NewGeoShapeProperty(), nil
+	case "completion":
+		return types.NewCompletionProperty(), nil
+	case "flattened":
+		return types.NewFlattenedProperty(), nil
+	case "object", "nested":
+		if t.Kind() != reflect.Struct {
+			if fieldType == "nested" {
+				return types.NewNestedProperty(), nil
+			}
+			return types.NewObjectProperty(), nil
+		}
+		properties, err := propertiesFromStruct(t)
+		if err != nil {
+			return nil, err
+		}
+		if fieldType == "nested" {
+			p := types.NewNestedProperty()
+			p.Properties = properties
+			return p, nil
+		}
+		p := types.NewObjectProperty()
+		p.Properties = properties
+		return p, nil
+	}
+	return nil, fmt.Errorf("desteklenmeyen alan tipi: %q", fieldType)
+}
+
+// defaultFieldType, tag'i olmayan alanlar için Go tipinden Elasticsearch tipini çıkarır
+func defaultFieldType(t reflect.Type) string {
+	if t == timeType {
+		return "date"
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return "text"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int8:
+		return "byte"
+	case reflect.Int16:
+		return "short"
+	case reflect.Int32:
+		return "integer"
+	case reflect.Int, reflect.Int64, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return "long"
+	case reflect.Uint, reflect.Uint64:
+		return "unsigned_long"
+	case reflect.Float32:
+		return "float"
+	case reflect.Float64:
+		return "double"
+	case reflect.Slice:
+		// []byte base64 olarak serileştirilir
+		return "binary"
+	case reflect.Struct:
+		return "object"
+	case reflect.Map:
+		return "flattened"
+	}
+	return ""
+}
+
+// keywordSubField, multi-field için .keyword alt alanını oluşturur; normalizer boşsa değer olduğu gibi saklanır
+func keywordSubField(normalizer string) *types.KeywordProperty {
+	ignoreAbove := keywordIgnoreAbove
+	p := types.NewKeywordProperty()
+	p.IgnoreAbove = &ignoreAbove
+	if normalizer != "" {
+		p.Normalizer = &normalizer
+	}
+	return p
+}
+
+// applyTextSubFields, `fields=ad:analyzer|ad2:analyzer2` seçeneğindeki text alt alanlarını ekler
+func applyTextSubFields(tag esTag, fields map[string]types.Property) error {
+	value, ok := tag.Options["fields"]
+	if !ok {
+		return nil
+	}
+	for _, entry := range strings.Split(value, "|") {
+		name, analyzer, found := strings.Cut(entry, ":")
+		if !found || name == "" || analyzer == "" {
+			return fmt.Errorf("geçersiz fields değeri %q, beklenen biçim ad:analyzer", entry)
+		}
+		p := types.NewTextProperty()
+		p.Analyzer = &analyzer
+		fields[name] = p
+	}
+	return nil
+}
+
+// optionString, seçenek tag'de varsa değerinin adresini, yoksa nil döner
+func optionString(tag esTag, name string) *string {
+	value, ok := tag.Options[name]
+	if !ok || value == "" {
+		return nil
+	}
+	return &value
+}
+
+// applyIndexOption, `index=false` seçeneğini property'ye uygular
+func applyIndexOption(tag esTag, index **bool) error {
+	value, ok := tag.Options["index"]
+	if !ok {
+		return nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return fmt.Errorf("geçersiz index değeri %q: %w", value, err)
+	}
+	*index = &b
+	return nil
+}
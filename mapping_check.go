@@ -0,0 +1,227 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+)
+
+/*
+	Mapping Uyuşmazlık Kontrolü:
+	Go tarafında beklenen mapping ile canlı indeksin mapping'ini karşılaştırır
+	Örneğin 05-Create-Index'teki CreateDocument "price" alanını "100" string'i olarak gönderdiğinde
+	dinamik mapping bu alanı text olarak oluşturur; burada bu durum type_mismatch olarak raporlanır
+*/
+
+// MappingDriftKind, tespit edilen uyuşmazlığın türüdür
+type MappingDriftKind string
+
+const (
+	// DriftTypeMismatch: alan iki tarafta da var ama tipleri farklı
+	DriftTypeMismatch MappingDriftKind = "type_mismatch"
+	// DriftMissingInIndex: struct'ta tanımlı alan indeks mapping'inde yok
+	DriftMissingInIndex MappingDriftKind = "missing_in_index"
+	// DriftNotInStruct: indekste (çoğunlukla dinamik olarak) oluşmuş ama struct'ta karşılığı olmayan alan
+	DriftNotInStruct MappingDriftKind = "not_in_struct"
+)
+
+// MappingDrift, tek bir alandaki uyuşmazlığı temsil eder
+type MappingDrift struct {
+	Index    string           `json:"index"`
+	Field    string           `json:"field"`
+	Kind     MappingDriftKind `json:"kind"`
+	Expected string           `json:"expected,omitempty"`
+	Actual   string           `json:"actual,omitempty"`
+}
+
+func (d MappingDrift) String() string {
+	switch d.Kind {
+	case DriftTypeMismatch:
+		return fmt.Sprintf("[%s] %s: beklenen tip %s, indeksteki tip %s", d.Index, d.Field, d.Expected, d.Actual)
+	case DriftMissingInIndex:
+		return fmt.Sprintf("[%s] %s: indeks mapping'inde yok (beklenen tip %s)", d.Index, d.Field, d.Expected)
+	case DriftNotInStruct:
+		return fmt.Sprintf("[%s] %s: struct'ta karşılığı yok (indeksteki tip %s)", d.Index, d.Field, d.Actual)
+	}
+	return fmt.Sprintf("[%s] %s: %s", d.Index, d.Field, d.Kind)
+}
+
+// MappingDriftError, başlangıç kontrolünde uyuşmazlık bulunduğunda dönen hatadır
+type MappingDriftError struct {
+	Drifts []MappingDrift
+}
+
+func (e *MappingDriftError) Error() string {
+	lines := make([]string, 0, len(e.Drifts))
+	for _, d := range e.Drifts {
+		lines = append(lines, d.String())
+	}
+	return fmt.Sprintf("mapping uyuşmazlığı (%d alan):\n%s", len(e.Drifts), strings.Join(lines, "\n"))
+}
+
+// CheckMapping, beklenen mapping'i canlı indeksin mapping'iyle karşılaştırır ve uyuşmazlıkları döner
+func CheckMapping(client *elasticsearch.TypedClient, indexName string, expected *types.TypeMapping) ([]MappingDrift, error) {
+	ctx := context.Background()
+
+	res, err := client.Indices.GetMapping().Index(indexName).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// İndeks adı bir alias ya da desen olabilir, bu yüzden dönen her indeks ayrı kontrol edilir
+	indexNames := make([]string, 0, len(res))
+	for name := range res {
+		indexNames = append(indexNames, name)
+	}
+	sort.Strings(indexNames)
+
+	var drifts []MappingDrift
+	for _, name := range indexNames {
+		record := res[name]
+		indexDrifts, err := DiffMappings(expected, &record.Mappings)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range indexDrifts {
+			d.Index = name
+			drifts = append(drifts, d)
+		}
+	}
+	return drifts, nil
+}
+
+// CheckStructMapping, struct'tan üretilen mapping'i canlı indeksle karşılaştırır
+func CheckStructMapping(client *elasticsearch.TypedClient, indexName string, v interface{}) ([]MappingDrift, error) {
+	expected, err := MappingFromStruct(v)
+	if err != nil {
+		return nil, err
+	}
+	return CheckMapping(client, indexName, expected)
+}
+
+// ValidateStructMapping, servis açılışında kullanılmak üzere uyuşmazlık varsa *MappingDriftError döner
+func ValidateStructMapping(client *elasticsearch.TypedClient, indexName string, v interface{}) error {
+	drifts, err := CheckStructMapping(client, indexName, v)
+	if err != nil {
+		return err
+	}
+	if len(drifts) > 0 {
+		return &MappingDriftError{Drifts: drifts}
+	}
+	return nil
+}
+
+// DiffMappings, iki mapping'i alan yolu (örn: author.first_name, name.keyword) bazında karşılaştırır
+func DiffMappings(expected, actual *types.TypeMapping) ([]MappingDrift, error) {
+	expectedFields, err := flattenMapping(expected)
+	if err != nil {
+		return nil, err
+	}
+	actualFields, err := flattenMapping(actual)
+	if err != nil {
+		return nil, err
+	}
+
+	var drifts []MappingDrift
+	for field, expectedType := range expectedFields {
+		actualType, ok := actualFields[field]
+		if !ok {
+			// Eksik bir alanın alt alanları (örn: brand.keyword) ayrıca raporlanmaz
+			if parent, _, found := cutLast(field, "."); found {
+				if _, ok := actualFields[parent]; !ok {
+					continue
+				}
+			}
+			drifts = append(drifts, MappingDrift{Field: field, Kind: DriftMissingInIndex, Expected: expectedType})
+			continue
+		}
+		if actualType != expectedType {
+			drifts = append(drifts, MappingDrift{Field: field, Kind: DriftTypeMismatch, Expected: expectedType, Actual: actualType})
+		}
+	}
+	for field, actualType := range actualFields {
+		if _, ok := expectedFields[field]; ok {
+			continue
+		}
+		// Tipi zaten uyuşmayan bir alanın dinamik alt alanları (örn: price.keyword) ayrıca raporlanmaz
+		if parent, _, found := cutLast(field, "."); found {
+			if _, ok := expectedFields[parent]; ok && actualFields[parent] != expectedFields[parent] {
+				continue
+			}
+		}
+		drifts = append(drifts, MappingDrift{Field: field, Kind: DriftNotInStruct, Actual: actualType})
+	}
+
+	sort.Slice(drifts, func(i, j int) bool {
+		return drifts[i].Field < drifts[j].Field
+	})
+	return drifts, nil
+}
+
+// flattenMapping, mapping'i "alan yolu -> tip" haritasına dönüştürür
+// Property'ler farklı Go tiplerinde geldiği için karşılaştırma JSON gösterimi üzerinden yapılır
+func flattenMapping(mapping *types.TypeMapping) (map[string]string, error) {
+	fields := map[string]string{}
+	if mapping == nil {
+		return fields, nil
+	}
+
+	data, err := json.Marshal(mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw struct {
+		Properties map[string]json.RawMessage `json:"properties"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	if err := flattenProperties("", raw.Properties, fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+func flattenProperties(prefix string, properties map[string]json.RawMessage, fields map[string]string) error {
+	for name, rawProperty := range properties {
+		var property struct {
+			Type       string                     `json:"type"`
+			Properties map[string]json.RawMessage `json:"properties"`
+			Fields     map[string]json.RawMessage `json:"fields"`
+		}
+		if err := json.Unmarshal(rawProperty, &property); err != nil {
+			return err
+		}
+
+		path := prefix + name
+		fieldType := property.Type
+		if fieldType == "" {
+			// Alt alanları olan ve tipi belirtilmemiş property'ler object'tir
+			fieldType = "object"
+		}
+		fields[path] = fieldType
+
+		if err := flattenProperties(path+".", property.Properties, fields); err != nil {
+			return err
+		}
+		if err := flattenProperties(path+".", property.Fields, fields); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cutLast, s'yi sep'in son geçtiği yerden ikiye böler
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+)
+
+func TestProductKeywordNormalizer(t *testing.T) {
+	mapping, err := MappingFromStruct(Product{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// .keyword aggregation ve gösterim için normalizer'sız kalır, eşleşme .norm alt alanında yapılır
+	tests := []struct {
+		field    string
+		subField string
+		want     string // alt alanın normalizer'ı, boşsa normalizer olmamalı; "-" ise alt alan olmamalı
+	}{
+		{"name", "keyword", ""},
+		{"name", NormalizedSubField, TurkishKeywordNormalizer},
+		{"category", "keyword", ""},
+		{"category", NormalizedSubField, TurkishKeywordNormalizer},
+		{"brand", "keyword", ""},
+		{"brand", NormalizedSubField, "-"},
+	}
+	for _, tt := range tests {
+		text, ok := mapping.Properties[tt.field].(*types.TextProperty)
+		if !ok {
+			t.Fatalf("%s text değil: %T", tt.field, mapping.Properties[tt.field])
+		}
+		property, exists := text.Fields[tt.subField]
+		if tt.want == "-" {
+			if exists {
+				t.Errorf("%s.%s olmamalı", tt.field, tt.subField)
+			}
+			continue
+		}
+		keyword, ok := property.(*types.KeywordProperty)
+		if !ok {
+			t.Fatalf("%s.%s keyword değil: %T", tt.field, tt.subField, property)
+		}
+
+		got := ""
+		if keyword.Normalizer != nil {
+			got = *keyword.Normalizer
+		}
+		if got != tt.want {
+			t.Errorf("%s.%s normalizer %q, %q olmalı", tt.field, tt.subField, got, tt.want)
+		}
+		if keyword.IgnoreAbove == nil || *keyword.IgnoreAbove != keywordIgnoreAbove {
+			t.Errorf("%s.%s ignore_above %v", tt.field, tt.subField, keyword.IgnoreAbove)
+		}
+	}
+}
+
+// fvh highlighter'ı varsayılan highlight alanlarında term vector ister
+func TestProductHighlightTermVectors(t *testing.T) {
+	mapping, err := MappingFromStruct(Product{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range []string{"name", "brand", "category"} {
+		text, ok := mapping.Properties[field].(*types.TextProperty)
+		if !ok {
+			t.Fatalf("%s text değil: %T", field, mapping.Properties[field])
+		}
+		if text.TermVector == nil || text.TermVector.Name != "with_positions_offsets" {
+			t.Errorf("%s term_vector %v, with_positions_offsets olmalı", field, text.TermVector)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/SadikSunbul/Go-Elasticsearch/q"
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/core/search"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/sortorder"
+)
+
+/*
+	Multi Search (_msearch):
+	Ürün sayfasının fiyat ve kategori, gelişmiş arama ve en çok satanlar sorguları
+	aşağıdaki istek fonksiyonlarında bir kez tanımlanır; MultiSearchProducts bunları tek istekte gönderir
+	Her alt isteğin sonucu ya da hatası, isteklerle aynı sırada ayrı ayrı döner:
+	bir sorgunun hata alması diğerlerinin sonuçlarını etkilemez
+*/
+
+// MultiSearchResult, _msearch içindeki tek bir isteğin sonucudur; Err doluysa Result nil'dir
+type MultiSearchResult struct {
+	Result *ProductSearchResult
+	Err    error
+}
+
+// MultiSearchProducts, verilen arama isteklerini products indeksinde tek bir _msearch ile çalıştırır
+func MultiSearchProducts(client *elasticsearch.TypedClient, requests ...*search.Request) ([]MultiSearchResult, error) {
+	ctx := context.Background()
+
+	if len(requests) == 0 {
+		return nil, nil
+	}
+
+	// _msearch gövdesi NDJSON'dır: her istek için bir başlık satırı ve bir gövde satırı
+	var body bytes.Buffer
+	header, err := json.Marshal(map[string]string{"index": "products"})
+	if err != nil {
+		return nil, err
+	}
+	for _, req := range requests {
+		data, err := json.Marshal(req)
+		if err != nil {
+			return nil, err
+		}
+		body.Write(header)
+		body.WriteByte('\n')
+		body.Write(data)
+		body.WriteByte('\n')
+	}
+
+	res, err := client.Msearch().Raw(&body).Perform(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode >= 300 {
+		errorResponse := types.NewElasticsearchError()
+		if err := json.Unmarshal(data, errorResponse); err != nil {
+			return nil, fmt.Errorf("msearch hatası (HTTP %d): %s", res.StatusCode, data)
+		}
+		if errorResponse.Status == 0 {
+			errorResponse.Status = res.StatusCode
+		}
+		return nil, errorResponse
+	}
+
+	var raw struct {
+		Responses []json.RawMessage `json:"responses"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	if len(raw.Responses) != len(requests) {
+		return nil, fmt.Errorf("msearch %d istek için %d yanıt döndü", len(requests), len(raw.Responses))
+	}
+
+	results := make([]MultiSearchResult, len(raw.Responses))
+	for i, item := range raw.Responses {
+		results[i] = decodeMultiSearchItem(item)
+	}
+	return results, nil
+}
+
+// decodeMultiSearchItem, _msearch yanıtındaki tek bir öğeyi sonuç ya da hata olarak çözer
+func decodeMultiSearchItem(item json.RawMessage) MultiSearchResult {
+	var probe struct {
+		Error json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal(item, &probe); err != nil {
+		return MultiSearchResult{Err: err}
+	}
+	if len(probe.Error) > 0 {
+		errorResponse := types.NewElasticsearchError()
+		if err := json.Unmarshal(item, errorResponse); err != nil {
+			return MultiSearchResult{Err: fmt.Errorf("msearch öğe hatası çözülemedi: %s", item)}
+		}
+		return MultiSearchResult{Err: errorResponse}
+	}
+
+	res := search.NewResponse()
+	if err := json.Unmarshal(item, res); err != nil {
+		return MultiSearchResult{Err: err}
+	}
+	result, err := decodeProductHits(res.Hits)
+	if err != nil {
+		return MultiSearchResult{Err: err}
+	}
+	return MultiSearchResult{Result: result}
+}
+
+// AdvancedSearchParams, gelişmiş arama sorgusunun parametreleridir; boş alanlar sorguya eklenmez
+type AdvancedSearchParams struct {
+	Brand     string
+	MinRating float64
+	InStock   bool
+}
+
+// PriceAndCategoryRequest, fiyat aralığındaki ve kategorideki ürünleri fiyata göre sıralayan search isteğidir
+// Fiyat puanı etkilemez, filter olarak eklenir; kategori Türkçe alt alanlarıyla birlikte aranır
+func PriceAndCategoryRequest(minPrice, maxPrice float64, category string) (*search.Request, error) {
+	query, err := q.Bool().
+		Filter(q.Range("price").Gte(minPrice).Lte(maxPrice)).
+		Must(q.MultiMatch(category, TurkishTextFields("category")...).Type("most_fields")).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return &search.Request{
+		Query: query,
+		Sort:  []types.SortCombinations{fieldSort("price", sortorder.Asc)},
+	}, nil
+}
+
+// AdvancedSearchRequest, marka, en düşük puan ve stok kriterleriyle ürünleri puana göre sıralayan search isteğidir
+func AdvancedSearchRequest(params AdvancedSearchParams) (*search.Request, error) {
+	conditions := q.Bool()
+	if params.Brand != "" {
+		conditions.Must(q.Match("brand", params.Brand))
+	}
+	if params.MinRating > 0 {
+		conditions.Filter(q.Range("rating").Gte(params.MinRating))
+	}
+	if params.InStock {
+		conditions.Filter(q.Range("stock_count").Gt(0))
+	}
+
+	query, err := conditions.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	size := 20
+	return &search.Request{
+		Query: query,
+		Sort:  []types.SortCombinations{fieldSort("rating", sortorder.Desc)},
+		Size:  &size,
+	}, nil
+}
+
+// MostSoldRequest, en çok satan limit kadar ürünü getiren search isteğidir
+func MostSoldRequest(limit int) *search.Request {
+	// match_all sorgusu hata dönmez
+	query, _ := q.MatchAll().Build()
+	return &search.Request{
+		Query: query,
+		Sort:  []types.SortCombinations{fieldSort("sold_count", sortorder.Desc)},
+		Size:  &limit,
+	}
+}
+
+// fieldSort, tek alanlı sıralama tanımı oluşturur
+func fieldSort(field string, order sortorder.SortOrder) types.SortCombinations {
+	return types.SortOptions{
+		SortOptions: map[string]types.FieldSort{
+			field: {Order: &order},
+		},
+	}
+}
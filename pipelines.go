@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/SadikSunbul/Go-Elasticsearch/indexops"
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/converttype"
+)
+
+/*
+	Ürün Ingest Pipeline'ı:
+	Ürünler farklı kaynaklardan kirli gelir: fiyatlar "100" gibi string, markalar " nIKE " gibi karışık harfli,
+	tarihler "24.09.2024" gibi farklı formatlarda. products-clean pipeline'ı bunları indekslemeden önce düzeltir
+
+	Pipeline "templates apply" ile kümeye yazılır ve products template'inin default_pipeline ayarıyla
+	products-* indekslerine bağlanır; mevcut bir indekse "pipelines attach <indeks>" ile bağlanır
+	"pipelines simulate" örnek kirli belgeleri kümeye yazmadan pipeline'dan geçirir
+*/
+
+// productPipelineName, ürünleri temizleyen ingest pipeline'ıdır
+const productPipelineName = "products-clean"
+
+// ProductPipeline, ürün belgelerini temizleyen pipeline tanımıdır
+func ProductPipeline() indexops.Pipeline {
+	return indexops.Pipeline{
+		Name:        productPipelineName,
+		Version:     3,
+		Description: "Ürün alanlarını kırpar, sayısal alanları çevirir, markayı ve tarihi normalize eder",
+		Processors: []types.ProcessorContainer{
+			indexops.Trim("name"),
+			indexops.Trim("brand"),
+			indexops.Trim("category"),
+
+			// " nIKE " -> "nike" -> "Nike"; brand.keyword üzerindeki terms sorguları tek bir yazımla eşleşir
+			indexops.Lowercase("brand"),
+			// Painless'ta String.join sadece Iterable kabul eder; kelimeler StringBuilder ile birleştirilir
+			indexops.Script(`
+				if (ctx.brand instanceof String && !ctx.brand.isEmpty()) {
+					StringBuilder brand = new StringBuilder();
+					for (String word : ctx.brand.splitOnToken(' ')) {
+						if (word.isEmpty()) {
+							continue;
+						}
+						if (brand.length() > 0) {
+							brand.append(' ');
+						}
+						brand.append(word.substring(0, 1).toUpperCase()).append(word.substring(1));
+					}
+					ctx.brand = brand.toString();
+				}`),
+
+			// Çevrilemeyen değer ("yüz lira") silinir ve ingest_error'a yazılır; belge mapping'e uyar ve indekslenir
+			indexops.DropOnFailure(indexops.Convert("price", converttype.Float)),
+			indexops.DropOnFailure(indexops.Convert("rating", converttype.Float)),
+			indexops.DropOnFailure(indexops.Convert("stock_count", converttype.Integer)),
+			indexops.DropOnFailure(indexops.Convert("sold_count", converttype.Integer)),
+			indexops.DropOnFailure(indexops.Convert("is_available", converttype.Boolean)),
+
+			// is_available gönderilmediyse stoktan hesaplanır
+			indexops.Script(`
+				if (ctx.is_available == null) {
+					ctx.is_available = ctx.stock_count != null && ctx.stock_count > 0;
+				}`),
+
+			indexops.DropOnFailure(indexops.Date("create_date", "Europe/Istanbul", "ISO8601", "yyyy-MM-dd", "dd.MM.yyyy")),
+			indexops.SetDefault("category", "Diğer"),
+			indexops.Set("ingested_at", "{{{_ingest.timestamp}}}"),
+
+			// Eski içe aktarıcının eklediği geçici alanlar indekslenmez
+			indexops.Remove("import_batch", "raw_payload"),
+		},
+		// Diğer processor'lar (örn: betikler) hata verirse kalan processor'lar atlanır ve hata ingest_error'a
+		// yazılır; belge o haliyle indekslenir, hâlâ mapping'e uymayan bir alan içeriyorsa yine reddedilir
+		OnFailure: []types.ProcessorContainer{
+			indexops.Set(indexops.FailureField, "{{{ _ingest.on_failure_message }}}"),
+		},
+	}
+}
+
+// runPipelinesCommand, "pipelines simulate|attach" komutlarını çalıştırır
+func runPipelinesCommand(client *elasticsearch.TypedClient, args []string) error {
+	switch {
+	case len(args) == 1 && args[0] == "simulate":
+		return simulateProductPipeline(client)
+	case len(args) == 2 && args[0] == "attach":
+		result, err := indexops.SetDefaultPipeline(client, args[1], productPipelineName)
+		if err != nil {
+			return err
+		}
+		fmt.Println(result)
+		return nil
+	default:
+		return fmt.Errorf("kullanım: pipelines simulate | attach <indeks>")
+	}
+}
+
+// simulateProductPipeline, örnek kirli ürünleri pipeline'dan geçirip sonuçlarını yazar
+func simulateProductPipeline(client *elasticsearch.TypedClient) error {
+	samples := []map[string]interface{}{
+		{"name": " Air Max 270 ", "brand": " nIKE ", "category": "Ayakkabı", "price": "100", "stock_count": "3", "create_date": "24.09.2024"},
+		{"name": "Ultraboost", "brand": "ADIDAS", "price": 2499.9, "stock_count": 0, "create_date": "2024-09-25", "import_batch": "b-17"},
+		{"name": "Bozuk Fiyat", "brand": "puma", "price": "yüz lira"},
+	}
+	docs := make([]interface{}, 0, len(samples))
+	for _, sample := range samples {
+		docs = append(docs, sample)
+	}
+
+	results, err := indexops.Simulate(client, ProductPipeline(), docs...)
+	if err != nil {
+		return err
+	}
+	for i, result := range results {
+		if result.Err != nil {
+			fmt.Printf("%d. belge reddedildi: %s\n", i+1, result.Err)
+			continue
+		}
+		fmt.Printf("%d. belge: %s\n", i+1, result.Source)
+	}
+	return nil
+}
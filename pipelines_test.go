@@ -0,0 +1,84 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/SadikSunbul/Go-Elasticsearch/indexops"
+	"github.com/elastic/go-elasticsearch/v8"
+)
+
+// products-clean pipeline'ı gerçek bir kümede Simulate ile çalıştırılır; pipeline kümeye yazılmaz
+// ELASTICSEARCH_URL verilmezse (örn: http://localhost:9200) test atlanır
+func TestProductPipeline(t *testing.T) {
+	address := os.Getenv("ELASTICSEARCH_URL")
+	if address == "" {
+		t.Skip("ELASTICSEARCH_URL verilmedi")
+	}
+	client, err := elasticsearch.NewTypedClient(elasticsearch.Config{Addresses: []string{address}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		input map[string]interface{}
+		want  map[string]interface{} // beklenen alanlar; nil değer alanın olmaması gerektiğini belirtir
+
+		wantError bool // ingest_error yazılmalı mı
+	}{
+		{
+			name:  "kirli alanlar temizlenir",
+			input: map[string]interface{}{"name": " Air Max 270 ", "brand": " nIKE ", "category": "Ayakkabı", "price": "100", "stock_count": "3"},
+			want:  map[string]interface{}{"name": "Air Max 270", "brand": "Nike", "price": 100.0, "stock_count": 3.0, "is_available": true},
+		},
+		{
+			name:  "çok kelimeli marka",
+			input: map[string]interface{}{"brand": "under  ARMOUR"},
+			want:  map[string]interface{}{"brand": "Under Armour"},
+		},
+		{
+			name:  "Türkçe tarih ve varsayılan kategori",
+			input: map[string]interface{}{"name": "Ultraboost", "create_date": "24.09.2024", "stock_count": 0, "import_batch": "b-17"},
+			want:  map[string]interface{}{"create_date": "2024-09-24T00:00:00.000+03:00", "category": "Diğer", "is_available": false, "import_batch": nil},
+		},
+		{
+			name:      "çevrilemeyen fiyat silinir, belge indekslenebilir kalır",
+			input:     map[string]interface{}{"name": "Bozuk Fiyat", "brand": "puma", "price": "yüz lira"},
+			want:      map[string]interface{}{"brand": "Puma", "price": nil, "category": "Diğer"},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := indexops.Simulate(client, ProductPipeline(), tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var doc map[string]interface{}
+			if err := results[0].Decode(&doc); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, ok := doc["ingested_at"]; !ok {
+				t.Error("ingested_at yazılmadı")
+			}
+			for field, want := range tt.want {
+				got, ok := doc[field]
+				if want == nil {
+					if ok {
+						t.Errorf("%s = %v, alan olmamalı", field, got)
+					}
+					continue
+				}
+				if got != want {
+					t.Errorf("%s = %v, %v olmalı", field, got, want)
+				}
+			}
+			if _, failed := doc[indexops.FailureField]; failed != tt.wantError {
+				t.Errorf("%s = %v", indexops.FailureField, doc[indexops.FailureField])
+			}
+		})
+	}
+}
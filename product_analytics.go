@@ -0,0 +1,356 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/SadikSunbul/Go-Elasticsearch/q"
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/core/search"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/sortorder"
+)
+
+/*
+	Satış Analizleri:
+	products indeksinde tek bir size=0 arama ile iç içe aggregation'lar çalıştırılır:
+	- revenue: price * sold_count script'li sum ile tahmini ciro
+	- top_brands: sold_count toplamına göre sıralanmış en çok satan markalar
+	- categories: kategori başına ortalama puan, fiyat yüzdelikleri, stokta olmayan ürün sayısı ve
+	  bucket_script ile ürün başına ortalama ciro
+	- brand_count: farklı marka sayısı (cardinality, yaklaşık)
+	Yanıttaki types.Aggregate union'ı tip dönüşümüyle (AggregatePrices'taki SumAggregate gibi) sade struct'lara çevrilir
+*/
+
+// revenueScript, ürünün tahmini cirosunu hesaplar; alanı olmayan ürünler 0 sayılır
+const revenueScript = "doc['price'].size() == 0 || doc['sold_count'].size() == 0 ? 0 : doc['price'].value * doc['sold_count'].value"
+
+// SalesAnalyticsOptions, ProductSalesAnalytics parametreleridir
+type SalesAnalyticsOptions struct {
+	Query      q.Builder // analize dahil edilecek ürünler, nil ise tümü
+	TopBrands  int       // listelenecek marka sayısı, 0 ise 10
+	Categories int       // listelenecek kategori sayısı, 0 ise 20
+	Percents   []float64 // fiyat yüzdelikleri, boşsa 25, 50, 75, 95
+}
+
+// SalesReport, ürün satış analizinin sonucudur
+type SalesReport struct {
+	ProductCount int64
+	TotalRevenue float64 // sum(price * sold_count)
+	BrandCount   int64   // farklı marka sayısı (yaklaşık)
+	OutOfStock   int64   // stock_count = 0 olan ürün sayısı
+	TopBrands    []BrandSales
+	Categories   []CategoryStats
+}
+
+// BrandSales, bir markanın satış özetidir
+type BrandSales struct {
+	Brand        string
+	ProductCount int64
+	SoldCount    int64
+	Revenue      float64
+}
+
+// CategoryStats, bir kategorinin satış ve fiyat özetidir
+type CategoryStats struct {
+	Category          string
+	ProductCount      int64
+	AvgRating         float64
+	Revenue           float64
+	RevenuePerProduct float64 // bucket_script: revenue / ürün sayısı
+	OutOfStock        int64
+	PricePercentiles  []Percentile
+}
+
+// Percentile, tek bir yüzdelik değeridir (örn: Percent 50 -> medyan fiyat)
+type Percentile struct {
+	Percent float64
+	Value   float64
+}
+
+// ProductSalesAnalytics, products indeksinde satış analizlerini tek istekte hesaplar
+func ProductSalesAnalytics(client *elasticsearch.TypedClient, options SalesAnalyticsOptions) (*SalesReport, error) {
+	ctx := context.Background()
+
+	if options.TopBrands <= 0 {
+		options.TopBrands = 10
+	}
+	if options.Categories <= 0 {
+		options.Categories = 20
+	}
+	if len(options.Percents) == 0 {
+		options.Percents = []float64{25, 50, 75, 95}
+	}
+
+	size := 0
+	request := &search.Request{
+		Size:           &size,
+		TrackTotalHits: true,
+		Aggregations:   salesAggregations(options),
+	}
+	if options.Query != nil {
+		query, err := options.Query.Build()
+		if err != nil {
+			return nil, err
+		}
+		request.Query = query
+	}
+
+	res, err := client.Search().Index("products").Request(request).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &SalesReport{}
+	if res.Hits.Total != nil {
+		report.ProductCount = res.Hits.Total.Value
+	}
+	if report.TotalRevenue, err = metricValue(res.Aggregations["revenue"]); err != nil {
+		return nil, err
+	}
+	if cardinality, ok := res.Aggregations["brand_count"].(*types.CardinalityAggregate); ok {
+		report.BrandCount = cardinality.Value
+	}
+	if report.OutOfStock, err = filterCount(res.Aggregations["out_of_stock"]); err != nil {
+		return nil, err
+	}
+	if report.TopBrands, err = decodeBrandSales(res.Aggregations["top_brands"]); err != nil {
+		return nil, err
+	}
+	if report.Categories, err = decodeCategoryStats(res.Aggregations["categories"]); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// salesAggregations, satış analizinin aggregation ağacını oluşturur
+func salesAggregations(options SalesAnalyticsOptions) map[string]types.Aggregations {
+	brandField := "brand.keyword"
+	categoryField := "category.keyword"
+	soldField := "sold_count"
+	ratingField := "rating"
+	priceField := "price"
+
+	percents := make([]types.Float64, len(options.Percents))
+	for i, percent := range options.Percents {
+		percents[i] = types.Float64(percent)
+	}
+	keyed := false
+	revenuePerProductScript := "params.count == 0 ? 0 : params.revenue / params.count"
+
+	return map[string]types.Aggregations{
+		"revenue":      revenueAggregation(),
+		"out_of_stock": outOfStockAggregation(),
+		"brand_count": {
+			Cardinality: &types.CardinalityAggregation{Field: &brandField},
+		},
+		"top_brands": {
+			Terms: &types.TermsAggregation{
+				Field: &brandField,
+				Size:  &options.TopBrands,
+				Order: map[string]sortorder.SortOrder{"sold": sortorder.Desc},
+			},
+			Aggregations: map[string]types.Aggregations{
+				"sold":    {Sum: &types.SumAggregation{Field: &soldField}},
+				"revenue": revenueAggregation(),
+			},
+		},
+		"categories": {
+			Terms: &types.TermsAggregation{
+				Field: &categoryField,
+				Size:  &options.Categories,
+			},
+			Aggregations: map[string]types.Aggregations{
+				"avg_rating":   {Avg: &types.AverageAggregation{Field: &ratingField}},
+				"revenue":      revenueAggregation(),
+				"out_of_stock": outOfStockAggregation(),
+				"price_percentiles": {
+					Percentiles: &types.PercentilesAggregation{
+						Field:    &priceField,
+						Percents: percents,
+						Keyed:    &keyed,
+					},
+				},
+				// Kategori bucket'ı içinde, hesaplanmış revenue ve belge sayısı üzerinden çalışır
+				"revenue_per_product": {
+					BucketScript: &types.BucketScriptAggregation{
+						BucketsPath: map[string]string{"revenue": "revenue", "count": "_count"},
+						Script:      &types.Script{Source: &revenuePerProductScript},
+					},
+				},
+			},
+		},
+	}
+}
+
+func revenueAggregation() types.Aggregations {
+	source := revenueScript
+	return types.Aggregations{
+		Sum: &types.SumAggregation{
+			Script: &types.Script{Source: &source},
+		},
+	}
+}
+
+func outOfStockAggregation() types.Aggregations {
+	// term sorgusu hata dönmez
+	filter, _ := q.Term("stock_count", 0).Build()
+	return types.Aggregations{
+		Filter: filter,
+	}
+}
+
+// decodeBrandSales, top_brands terms aggregation'ını BrandSales listesine çevirir
+func decodeBrandSales(aggregate types.Aggregate) ([]BrandSales, error) {
+	buckets, err := stringTermsBuckets(aggregate)
+	if err != nil {
+		return nil, err
+	}
+
+	brands := make([]BrandSales, 0, len(buckets))
+	for _, bucket := range buckets {
+		sold, err := metricValue(bucket.Aggregations["sold"])
+		if err != nil {
+			return nil, err
+		}
+		revenue, err := metricValue(bucket.Aggregations["revenue"])
+		if err != nil {
+			return nil, err
+		}
+		brands = append(brands, BrandSales{
+			Brand:        fmt.Sprint(bucket.Key),
+			ProductCount: bucket.DocCount,
+			SoldCount:    int64(sold),
+			Revenue:      revenue,
+		})
+	}
+	return brands, nil
+}
+
+// decodeCategoryStats, categories terms aggregation'ını CategoryStats listesine çevirir
+func decodeCategoryStats(aggregate types.Aggregate) ([]CategoryStats, error) {
+	buckets, err := stringTermsBuckets(aggregate)
+	if err != nil {
+		return nil, err
+	}
+
+	categories := make([]CategoryStats, 0, len(buckets))
+	for _, bucket := range buckets {
+		stats := CategoryStats{
+			Category:     fmt.Sprint(bucket.Key),
+			ProductCount: bucket.DocCount,
+		}
+		if stats.AvgRating, err = metricValue(bucket.Aggregations["avg_rating"]); err != nil {
+			return nil, err
+		}
+		if stats.Revenue, err = metricValue(bucket.Aggregations["revenue"]); err != nil {
+			return nil, err
+		}
+		if stats.RevenuePerProduct, err = metricValue(bucket.Aggregations["revenue_per_product"]); err != nil {
+			return nil, err
+		}
+		if stats.OutOfStock, err = filterCount(bucket.Aggregations["out_of_stock"]); err != nil {
+			return nil, err
+		}
+		if stats.PricePercentiles, err = decodePercentiles(bucket.Aggregations["price_percentiles"]); err != nil {
+			return nil, err
+		}
+		categories = append(categories, stats)
+	}
+	return categories, nil
+}
+
+// stringTermsBuckets, keyword alan üzerindeki terms aggregation'ın bucket'larını döner
+func stringTermsBuckets(aggregate types.Aggregate) ([]types.StringTermsBucket, error) {
+	switch terms := aggregate.(type) {
+	case *types.StringTermsAggregate:
+		buckets, ok := terms.Buckets.([]types.StringTermsBucket)
+		if !ok {
+			return nil, fmt.Errorf("beklenmeyen bucket tipi: %T", terms.Buckets)
+		}
+		return buckets, nil
+	case *types.UnmappedTermsAggregate:
+		// Alan hiçbir shard'da yoksa (örn: boş indeks) bucket dönmez
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("beklenmeyen aggregation tipi: %T", aggregate)
+	}
+}
+
+// metricValue, tek değerli metric aggregation'ların (sum, avg, bucket_script...) değerini döner
+// Değer yoksa (eşleşen belge yok) 0 döner
+func metricValue(aggregate types.Aggregate) (float64, error) {
+	var value *types.Float64
+	switch metric := aggregate.(type) {
+	case *types.SumAggregate:
+		value = metric.Value
+	case *types.AvgAggregate:
+		value = metric.Value
+	case *types.MinAggregate:
+		value = metric.Value
+	case *types.MaxAggregate:
+		value = metric.Value
+	case *types.SimpleValueAggregate:
+		value = metric.Value
+	default:
+		return 0, fmt.Errorf("beklenmeyen aggregation tipi: %T", aggregate)
+	}
+	if value == nil {
+		return 0, nil
+	}
+	return float64(*value), nil
+}
+
+// filterCount, filter aggregation'ına uyan belge sayısını döner
+func filterCount(aggregate types.Aggregate) (int64, error) {
+	filter, ok := aggregate.(*types.FilterAggregate)
+	if !ok {
+		return 0, fmt.Errorf("beklenmeyen aggregation tipi: %T", aggregate)
+	}
+	return filter.DocCount, nil
+}
+
+// decodePercentiles, percentiles aggregation'ını yüzdeye göre sıralı listeye çevirir
+func decodePercentiles(aggregate types.Aggregate) ([]Percentile, error) {
+	percentiles, ok := aggregate.(*types.TDigestPercentilesAggregate)
+	if !ok {
+		return nil, fmt.Errorf("beklenmeyen aggregation tipi: %T", aggregate)
+	}
+
+	var result []Percentile
+	switch values := percentiles.Values.(type) {
+	case []types.ArrayPercentilesItem:
+		for _, item := range values {
+			percent, err := strconv.ParseFloat(item.Key, 64)
+			if err != nil {
+				return nil, fmt.Errorf("geçersiz yüzdelik anahtarı %q: %w", item.Key, err)
+			}
+			if item.Value == nil {
+				continue
+			}
+			result = append(result, Percentile{Percent: percent, Value: float64(*item.Value)})
+		}
+	case types.KeyedPercentiles:
+		for key, raw := range values {
+			percent, err := strconv.ParseFloat(key, 64)
+			if err != nil {
+				return nil, fmt.Errorf("geçersiz yüzdelik anahtarı %q: %w", key, err)
+			}
+			value, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				// Eşleşen belge yoksa değer null döner
+				continue
+			}
+			result = append(result, Percentile{Percent: percent, Value: value})
+		}
+	default:
+		return nil, fmt.Errorf("beklenmeyen percentiles tipi: %T", percentiles.Values)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Percent < result[j].Percent
+	})
+	return result, nil
+}
@@ -0,0 +1,380 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/core/search"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/fieldvaluefactormodifier"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/functionboostmode"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/functionscoremode"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/highlightertype"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/textquerytype"
+)
+
+/*
+	Serbest Metin Ürün Arama:
+	multi_match ile name, brand ve category alanlarında fuzzy arama yapılır
+	Sorgu ifadesi name alanında birebir geçiyorsa match_phrase ile ek puan verilir
+	function_score ile rating, sold_count ve create_date yeniliği skora eklenir
+	Tüm ağırlıklar ProductRelevance ile ayarlanabilir
+*/
+
+// ProductRelevance, ürün aramasındaki alan ve fonksiyon ağırlıklarını tutar
+// Sıfır değerli alanlar DefaultProductRelevance'taki değeri alır; sadece değiştirilecek alanlar verilir
+type ProductRelevance struct {
+	NameBoost     float64 // name alanının ağırlığı (name^3)
+	BrandBoost    float64 // brand alanının ağırlığı (brand^2)
+	CategoryBoost float64 // category alanının ağırlığı
+	Fuzziness     string  // multi_match fuzziness değeri, "0" ise fuzzy arama yapılmaz
+	PhraseBoost   float64 // ifade name alanında birebir geçtiğinde verilen ek puan, negatifse kapalı
+
+	RatingWeight    float64 // rating alanının skora etkisi, negatifse kapalı
+	SoldCountWeight float64 // sold_count alanının (log1p ile) skora etkisi, negatifse kapalı
+	RecencyWeight   float64 // create_date yeniliğinin skora etkisi, negatifse kapalı
+	RecencyScale    string  // yenilik puanının RecencyDecay'e düştüğü süre (örn: 30d)
+	RecencyDecay    float64 // RecencyScale kadar eski ürünün alacağı yenilik puanı
+}
+
+// DefaultProductRelevance, varsayılan arama ağırlıklarını döner
+func DefaultProductRelevance() ProductRelevance {
+	return ProductRelevance{
+		NameBoost:       3,
+		BrandBoost:      2,
+		CategoryBoost:   1,
+		Fuzziness:       "AUTO",
+		PhraseBoost:     2,
+		RatingWeight:    1,
+		SoldCountWeight: 1,
+		RecencyWeight:   1,
+		RecencyScale:    "30d",
+		RecencyDecay:    0.5,
+	}
+}
+
+// merge, override'daki sıfır olmayan alanları r'nin üzerine yazar
+// Ağırlık 0 verilemeyen alanlarda (name^0 gibi) sıfır değer "varsayılanı kullan" anlamına gelir
+func (r ProductRelevance) merge(override ProductRelevance) ProductRelevance {
+	mergeFloat := func(target *float64, value float64) {
+		if value != 0 {
+			*target = value
+		}
+	}
+	mergeString := func(target *string, value string) {
+		if value != "" {
+			*target = value
+		}
+	}
+
+	mergeFloat(&r.NameBoost, override.NameBoost)
+	mergeFloat(&r.BrandBoost, override.BrandBoost)
+	mergeFloat(&r.CategoryBoost, override.CategoryBoost)
+	mergeString(&r.Fuzziness, override.Fuzziness)
+	mergeFloat(&r.PhraseBoost, override.PhraseBoost)
+	mergeFloat(&r.RatingWeight, override.RatingWeight)
+	mergeFloat(&r.SoldCountWeight, override.SoldCountWeight)
+	mergeFloat(&r.RecencyWeight, override.RecencyWeight)
+	mergeString(&r.RecencyScale, override.RecencyScale)
+	mergeFloat(&r.RecencyDecay, override.RecencyDecay)
+	return r
+}
+
+// ProductQuery, SearchProducts'a verilen arama isteğidir
+type ProductQuery struct {
+	Text      string
+	From      int
+	Size      int
+	Relevance *ProductRelevance // verilen alanlar DefaultProductRelevance'ın üzerine yazılır
+	Highlight *HighlightOptions // nil ise highlight istenmez
+	Suggest   *SuggestOptions   // nil ise yazım önerisi istenmez
+}
+
+// HighlightOptions, arama sonuçlarındaki vurgulanmış parçacıkların ayarlarıdır
+// fvh tipi için alanın mapping'inde term_vector=with_positions_offsets tanımlı olmalıdır;
+// Product'ta sadece name, brand ve category böyle eşlenir, diğer alanlarda unified kullanılmalıdır
+type HighlightOptions struct {
+	Fields            []string                         // boşsa name, brand ve category vurgulanır
+	FragmentSize      int                              // parçacık uzunluğu (karakter), 0 ise Elasticsearch varsayılanı
+	NumberOfFragments int                              // alan başına parçacık sayısı, 0 ise Elasticsearch varsayılanı
+	PreTags           []string                         // örn: []string{"<em>"}
+	PostTags          []string                         // örn: []string{"</em>"}
+	Type              *highlightertype.HighlighterType // unified, fvh veya plain
+}
+
+// ProductHit, arama sonucundaki tek bir ürünü temsil eder
+type ProductHit struct {
+	ID        string
+	Score     float64
+	Product   Product
+	Highlight map[string][]string // alan adı -> vurgulanmış parçacıklar
+}
+
+// ProductSearchResult, SearchProducts'ın döndüğü tipli sonuçtur
+type ProductSearchResult struct {
+	Total       int64
+	Hits        []ProductHit
+	Suggestions []Suggestion // skora göre sıralı "bunu mu demek istediniz?" önerileri
+}
+
+// SearchProducts, ürünlerde serbest metin araması yapar ve sonuçları ilgiye göre sıralar
+func SearchProducts(client *elasticsearch.TypedClient, q ProductQuery) (*ProductSearchResult, error) {
+	ctx := context.Background()
+
+	res, body, err := doSearch(ctx, client.Search().
+		Index("products").
+		Request(buildProductSearchRequest(q)))
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := decodeProductHits(res.Hits)
+	if err != nil {
+		return nil, err
+	}
+
+	suggest, err := decodeRawSuggest(body)
+	if err != nil {
+		return nil, err
+	}
+	result.Suggestions = decodeSuggestions(q.Text, suggest, q.Suggest != nil && q.Suggest.Collate)
+	return result, nil
+}
+
+// doSearch, search isteğini çalıştırır ve tipli yanıtla birlikte ham yanıt gövdesini de döner
+// Ham gövde, typedapi'nin eksik çözdüğü bölümler (örn: suggest) için kullanılır
+func doSearch(ctx context.Context, req *search.Search) (*search.Response, []byte, error) {
+	res, err := req.TypedKeys(true).Perform(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if res.StatusCode >= 300 {
+		errorResponse := types.NewElasticsearchError()
+		if err := json.Unmarshal(body, errorResponse); err != nil {
+			return nil, nil, fmt.Errorf("arama hatası (HTTP %d): %s", res.StatusCode, body)
+		}
+		if errorResponse.Status == 0 {
+			errorResponse.Status = res.StatusCode
+		}
+		return nil, nil, errorResponse
+	}
+
+	response := search.NewResponse()
+	if err := json.Unmarshal(body, response); err != nil {
+		return nil, nil, err
+	}
+	return response, body, nil
+}
+
+// buildProductSearchRequest, ProductQuery'den search isteğini oluşturur
+func buildProductSearchRequest(q ProductQuery) *search.Request {
+	relevance := DefaultProductRelevance()
+	if q.Relevance != nil {
+		relevance = relevance.merge(*q.Relevance)
+	}
+
+	size := q.Size
+	if size <= 0 {
+		size = 20
+	}
+	from := q.From
+
+	return &search.Request{
+		From:      &from,
+		Size:      &size,
+		Query:     productRelevanceQuery(q.Text, relevance),
+		Highlight: buildHighlight(q.Highlight),
+		Suggest:   buildSuggester(q.Text, q.Suggest),
+	}
+}
+
+// buildHighlight, HighlightOptions'ı search isteğinin highlight bölümüne dönüştürür
+func buildHighlight(options *HighlightOptions) *types.Highlight {
+	if options == nil {
+		return nil
+	}
+
+	fields := options.Fields
+	if len(fields) == 0 {
+		fields = []string{"name", "brand", "category"}
+	}
+
+	highlight := &types.Highlight{
+		Fields:   make(map[string]types.HighlightField, len(fields)),
+		PreTags:  options.PreTags,
+		PostTags: options.PostTags,
+		Type:     options.Type,
+	}
+	for _, field := range fields {
+		highlight.Fields[field] = types.HighlightField{}
+	}
+	if options.FragmentSize > 0 {
+		fragmentSize := options.FragmentSize
+		highlight.FragmentSize = &fragmentSize
+	}
+	if options.NumberOfFragments > 0 {
+		numberOfFragments := options.NumberOfFragments
+		highlight.NumberOfFragments = &numberOfFragments
+	}
+	return highlight
+}
+
+// productRelevanceQuery, metin sorgusunu function_score ile sarmalar
+func productRelevanceQuery(text string, relevance ProductRelevance) *types.Query {
+	textQuery := types.Query{MatchAll: types.NewMatchAllQuery()}
+	if text != "" {
+		textQuery = types.Query{
+			Bool: &types.BoolQuery{
+				Must: []types.Query{
+					{MultiMatch: productMultiMatch(text, relevance)},
+				},
+			},
+		}
+		if relevance.PhraseBoost > 0 {
+			phraseBoost := float32(relevance.PhraseBoost)
+			textQuery.Bool.Should = append(textQuery.Bool.Should, types.Query{
+				MatchPhrase: map[string]types.MatchPhraseQuery{
+					"name": {Query: text, Boost: &phraseBoost},
+				},
+			})
+		}
+	}
+
+	functions := productScoreFunctions(relevance)
+	if len(functions) == 0 {
+		return &textQuery
+	}
+
+	// Fonksiyon skorları toplanıp metin skoruyla çarpılır; hiçbir fonksiyonun
+	// eşleşmediği ürünlerin skoru sıfırlanmasın diye taban ağırlık 1 eklenir
+	baseWeight := types.Float64(1)
+	functions = append(functions, types.FunctionScore{Weight: &baseWeight})
+
+	return &types.Query{
+		FunctionScore: &types.FunctionScoreQuery{
+			Query:     &textQuery,
+			Functions: functions,
+			ScoreMode: &functionscoremode.Sum,
+			BoostMode: &functionboostmode.Multiply,
+		},
+	}
+}
+
+// productMultiMatch, ağırlıklı alanlarla multi_match sorgusunu oluşturur
+func productMultiMatch(text string, relevance ProductRelevance) *types.MultiMatchQuery {
+	var fields []string
+	for _, field := range TurkishTextFields("name") {
+		fields = append(fields, boostedField(field, relevance.NameBoost))
+	}
+	fields = append(fields, boostedField("brand", relevance.BrandBoost))
+	for _, field := range TurkishTextFields("category") {
+		fields = append(fields, boostedField(field, relevance.CategoryBoost))
+	}
+
+	query := &types.MultiMatchQuery{
+		Query:  text,
+		Fields: fields,
+		Type:   &textquerytype.Mostfields,
+	}
+	if relevance.Fuzziness != "" && relevance.Fuzziness != "0" {
+		query.Fuzziness = relevance.Fuzziness
+	}
+	return query
+}
+
+// productScoreFunctions, popülerlik ve yenilik fonksiyonlarını oluşturur
+func productScoreFunctions(relevance ProductRelevance) []types.FunctionScore {
+	var functions []types.FunctionScore
+
+	if relevance.RatingWeight > 0 {
+		weight := types.Float64(relevance.RatingWeight)
+		missing := types.Float64(0)
+		functions = append(functions, types.FunctionScore{
+			FieldValueFactor: &types.FieldValueFactorScoreFunction{
+				Field:    "rating",
+				Modifier: &fieldvaluefactormodifier.Log1p,
+				Missing:  &missing,
+			},
+			Weight: &weight,
+		})
+	}
+
+	if relevance.SoldCountWeight > 0 {
+		weight := types.Float64(relevance.SoldCountWeight)
+		missing := types.Float64(0)
+		functions = append(functions, types.FunctionScore{
+			FieldValueFactor: &types.FieldValueFactorScoreFunction{
+				Field:    "sold_count",
+				Modifier: &fieldvaluefactormodifier.Log1p,
+				Missing:  &missing,
+			},
+			Weight: &weight,
+		})
+	}
+
+	if relevance.RecencyWeight > 0 && relevance.RecencyScale != "" {
+		weight := types.Float64(relevance.RecencyWeight)
+		decay := types.Float64(relevance.RecencyDecay)
+		origin := "now"
+		functions = append(functions, types.FunctionScore{
+			Gauss: &types.DateDecayFunction{
+				DecayFunctionBaseDateMathDuration: map[string]types.DecayPlacementDateMathDuration{
+					"create_date": {
+						Origin: &origin,
+						Scale:  relevance.RecencyScale,
+						Decay:  &decay,
+					},
+				},
+			},
+			Weight: &weight,
+		})
+	}
+
+	return functions
+}
+
+// boostedField, alan adına ağırlık ekler (name^3); ağırlık 1 ise alan adı olduğu gibi döner
+func boostedField(field string, boost float64) string {
+	if boost == 1 {
+		return field
+	}
+	return fmt.Sprintf("%s^%g", field, boost)
+}
+
+// decodeProductHits, typed yanıttaki hits bölümünü ProductSearchResult'a dönüştürür
+func decodeProductHits(hits types.HitsMetadata) (*ProductSearchResult, error) {
+	result := &ProductSearchResult{}
+	if hits.Total != nil {
+		result.Total = hits.Total.Value
+	}
+
+	for _, hit := range hits.Hits {
+		// _source kapalı ya da filtrelenmiş sorgularda gövde boş gelir; ürün alanları boş kalır
+		var product Product
+		if len(hit.Source_) > 0 {
+			if err := json.Unmarshal(hit.Source_, &product); err != nil {
+				return nil, err
+			}
+		}
+
+		productHit := ProductHit{Product: product, Highlight: hit.Highlight}
+		if hit.Id_ != nil {
+			productHit.ID = *hit.Id_
+		}
+		if hit.Score_ != nil {
+			productHit.Score = float64(*hit.Score_)
+		}
+		result.Hits = append(result.Hits, productHit)
+	}
+	return result, nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+)
+
+func TestProductRelevanceMerge(t *testing.T) {
+	defaults := DefaultProductRelevance()
+
+	tests := []struct {
+		name     string
+		override ProductRelevance
+		want     func(r *ProductRelevance) // varsayılanlardan beklenen farklar
+	}{
+		{"boş override", ProductRelevance{}, func(r *ProductRelevance) {}},
+		{
+			// Sadece verilen alan değişir; brand ve category brand^0/category^0 olmaz
+			"tek alan",
+			ProductRelevance{NameBoost: 5},
+			func(r *ProductRelevance) { r.NameBoost = 5 },
+		},
+		{
+			"fonksiyonlar kapatılır",
+			ProductRelevance{RatingWeight: -1, RecencyWeight: -1, Fuzziness: "0"},
+			func(r *ProductRelevance) { r.RatingWeight, r.RecencyWeight, r.Fuzziness = -1, -1, "0" },
+		},
+	}
+	for _, tt := range tests {
+		want := defaults
+		tt.want(&want)
+		if got := defaults.merge(tt.override); got != want {
+			t.Errorf("%s:\n%+v\nolmalı:\n%+v", tt.name, got, want)
+		}
+	}
+}
+
+func TestProductMultiMatch(t *testing.T) {
+	relevance := DefaultProductRelevance().merge(ProductRelevance{NameBoost: 5, Fuzziness: "0"})
+	query := productMultiMatch("nike", relevance)
+
+	want := []string{"name^5", "name.folded^5", "brand^2", "category", "category.folded"}
+	if len(query.Fields) != len(want) {
+		t.Fatalf("alanlar %v, %v olmalı", query.Fields, want)
+	}
+	for i := range want {
+		if query.Fields[i] != want[i] {
+			t.Errorf("alanlar %v, %v olmalı", query.Fields, want)
+			break
+		}
+	}
+	if query.Fuzziness != nil {
+		t.Errorf("fuzziness %v, gönderilmemeli", query.Fuzziness)
+	}
+
+	if functions := productScoreFunctions(DefaultProductRelevance().merge(ProductRelevance{RatingWeight: -1})); len(functions) != 2 {
+		t.Errorf("%d fonksiyon, rating kapalıyken 2 olmalı", len(functions))
+	}
+}
+
+func TestDecodeProductHits(t *testing.T) {
+	id, score := "1", types.Float64(1.5)
+	total := &types.TotalHits{Value: 2}
+	hits := types.HitsMetadata{
+		Total: total,
+		Hits: []types.Hit{
+			{Id_: &id, Score_: &score, Source_: json.RawMessage(`{"name":"Air Max"}`)},
+			// _source: false ya da tüm alanları hariç tutan sorgular
+			{Id_: &id},
+		},
+	}
+
+	result, err := decodeProductHits(hits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Total != 2 || len(result.Hits) != 2 {
+		t.Fatalf("sonuç %+v", result)
+	}
+	if result.Hits[0].Product.Name != "Air Max" || result.Hits[0].Score != 1.5 {
+		t.Errorf("ilk sonuç %+v", result.Hits[0])
+	}
+	if result.Hits[1].ID != "1" || result.Hits[1].Product.Name != "" {
+		t.Errorf("_source'suz sonuç %+v", result.Hits[1])
+	}
+}
@@ -0,0 +1,198 @@
+package q
+
+import (
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/childscoremode"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/fieldvaluefactormodifier"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/functionboostmode"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/functionscoremode"
+)
+
+// BoolQuery, must/filter/should/must_not ile sorguları birleştiren bool oluşturucusudur
+type BoolQuery struct {
+	query types.BoolQuery
+	err   error
+}
+
+// Bool, boş bir bool sorgusu başlatır
+func Bool() *BoolQuery {
+	return &BoolQuery{}
+}
+
+// Must, eşleşmesi zorunlu ve skora katkı yapan sorguları ekler
+func (b *BoolQuery) Must(queries ...Builder) *BoolQuery {
+	return b.add(&b.query.Must, queries)
+}
+
+// Filter, eşleşmesi zorunlu ama skora katkı yapmayan (önbelleğe alınabilen) sorguları ekler
+func (b *BoolQuery) Filter(queries ...Builder) *BoolQuery {
+	return b.add(&b.query.Filter, queries)
+}
+
+// Should, eşleştiğinde skoru artıran sorguları ekler
+func (b *BoolQuery) Should(queries ...Builder) *BoolQuery {
+	return b.add(&b.query.Should, queries)
+}
+
+// MustNot, eşleşmemesi gereken sorguları ekler
+func (b *BoolQuery) MustNot(queries ...Builder) *BoolQuery {
+	return b.add(&b.query.MustNot, queries)
+}
+
+// MinimumShouldMatch, should sorgularından en az kaçının eşleşmesi gerektiğini belirler (örn: 1 ya da "75%")
+func (b *BoolQuery) MinimumShouldMatch(v interface{}) *BoolQuery {
+	b.query.MinimumShouldMatch = v
+	return b
+}
+
+func (b *BoolQuery) Boost(boost float32) *BoolQuery {
+	b.query.Boost = float32Ptr(boost)
+	return b
+}
+
+// IsEmpty, bool sorgusuna hiç koşul eklenmediyse true döner
+func (b *BoolQuery) IsEmpty() bool {
+	return len(b.query.Must) == 0 && len(b.query.Filter) == 0 &&
+		len(b.query.Should) == 0 && len(b.query.MustNot) == 0
+}
+
+func (b *BoolQuery) Build() (*types.Query, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	query := b.query
+	return &types.Query{Bool: &query}, nil
+}
+
+// add, sorguları ilgili listeye ekler; ilk hata saklanır ve Build'den döner
+func (b *BoolQuery) add(target *[]types.Query, queries []Builder) *BoolQuery {
+	built, err := build(queries)
+	if err != nil {
+		if b.err == nil {
+			b.err = err
+		}
+		return b
+	}
+	*target = append(*target, built...)
+	return b
+}
+
+// NestedQuery, nested alanlardaki alt belgeleri sorgulayan nested oluşturucusudur
+type NestedQuery struct {
+	query types.NestedQuery
+	err   error
+}
+
+// Nested, path altındaki her nested nesneyi ayrı belge olarak sorgular
+// query nil ise en az bir nested nesnesi olan belgeler eşleşir (match_all)
+func Nested(path string, query Builder) *NestedQuery {
+	if query == nil {
+		query = MatchAll()
+	}
+	built, err := query.Build()
+	return &NestedQuery{query: types.NestedQuery{Path: path, Query: built}, err: err}
+}
+
+// ScoreMode, eşleşen alt belgelerin skorunun üst belgeye nasıl yansıyacağını belirler: avg, max, min, sum, none
+func (n *NestedQuery) ScoreMode(mode string) *NestedQuery {
+	n.query.ScoreMode = &childscoremode.ChildScoreMode{Name: mode}
+	return n
+}
+
+// IgnoreUnmapped, path tanımlı olmayan indekslerde hata yerine eşleşmesiz sonuç döner
+func (n *NestedQuery) IgnoreUnmapped() *NestedQuery {
+	ignoreUnmapped := true
+	n.query.IgnoreUnmapped = &ignoreUnmapped
+	return n
+}
+
+func (n *NestedQuery) Build() (*types.Query, error) {
+	if n.err != nil {
+		return nil, n.err
+	}
+	query := n.query
+	return &types.Query{Nested: &query}, nil
+}
+
+// FunctionScoreQuery, sorgu skorunu fonksiyonlarla değiştiren function_score oluşturucusudur
+type FunctionScoreQuery struct {
+	query types.FunctionScoreQuery
+	err   error
+}
+
+// FunctionScore, verilen sorgunun skorunu fonksiyonlarla yeniden hesaplar
+// query nil ise sorgu gönderilmez; Elasticsearch bu durumda tüm belgeleri (match_all) puanlar
+func FunctionScore(query Builder) *FunctionScoreQuery {
+	built, err := buildOne(query)
+	return &FunctionScoreQuery{query: types.FunctionScoreQuery{Query: built}, err: err}
+}
+
+// FieldValueFactor, sayısal bir alanın değerini skora katar (modifier: none, log1p, sqrt ...)
+func (f *FunctionScoreQuery) FieldValueFactor(field string, factor float64, modifier string, weight float64) *FunctionScoreQuery {
+	fn := &types.FieldValueFactorScoreFunction{Field: field}
+	if factor != 0 {
+		factorValue := types.Float64(factor)
+		fn.Factor = &factorValue
+	}
+	if modifier != "" {
+		fn.Modifier = &fieldvaluefactormodifier.FieldValueFactorModifier{Name: modifier}
+	}
+	missing := types.Float64(0)
+	fn.Missing = &missing
+
+	return f.add(types.FunctionScore{FieldValueFactor: fn}, weight)
+}
+
+// DateDecay, tarih alanı origin'den uzaklaştıkça skoru düşüren gauss fonksiyonunu ekler
+// (örn: origin "now", scale "30d", decay 0.5)
+func (f *FunctionScoreQuery) DateDecay(field, origin, scale string, decay float64, weight float64) *FunctionScoreQuery {
+	decayValue := types.Float64(decay)
+	return f.add(types.FunctionScore{
+		Gauss: &types.DateDecayFunction{
+			DecayFunctionBaseDateMathDuration: map[string]types.DecayPlacementDateMathDuration{
+				field: {Origin: stringPtr(origin), Scale: scale, Decay: &decayValue},
+			},
+		},
+	}, weight)
+}
+
+// Weight, filtreyle eşleşen belgelere sabit ağırlık ekler; filter nil ise tüm belgelere uygulanır
+func (f *FunctionScoreQuery) Weight(filter Builder, weight float64) *FunctionScoreQuery {
+	built, err := buildOne(filter)
+	if err != nil {
+		if f.err == nil {
+			f.err = err
+		}
+		return f
+	}
+	return f.add(types.FunctionScore{Filter: built}, weight)
+}
+
+// ScoreMode, fonksiyon skorlarının nasıl birleştirileceğini belirler: multiply, sum, avg, first, max, min
+func (f *FunctionScoreQuery) ScoreMode(mode string) *FunctionScoreQuery {
+	f.query.ScoreMode = &functionscoremode.FunctionScoreMode{Name: mode}
+	return f
+}
+
+// BoostMode, fonksiyon skorunun sorgu skoruyla nasıl birleştirileceğini belirler: multiply, replace, sum ...
+func (f *FunctionScoreQuery) BoostMode(mode string) *FunctionScoreQuery {
+	f.query.BoostMode = &functionboostmode.FunctionBoostMode{Name: mode}
+	return f
+}
+
+func (f *FunctionScoreQuery) add(fn types.FunctionScore, weight float64) *FunctionScoreQuery {
+	if weight != 0 {
+		weightValue := types.Float64(weight)
+		fn.Weight = &weightValue
+	}
+	f.query.Functions = append(f.query.Functions, fn)
+	return f
+}
+
+func (f *FunctionScoreQuery) Build() (*types.Query, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	query := f.query
+	return &types.Query{FunctionScore: &query}, nil
+}
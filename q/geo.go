@@ -0,0 +1,31 @@
+package q
+
+import (
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+)
+
+// GeoDistance, geo_point alanı verilen noktaya distance (örn: "10km") mesafede olan belgeleri arar
+func GeoDistance(field string, lat, lon float64, distance string) Builder {
+	return Raw(&types.Query{
+		GeoDistance: &types.GeoDistanceQuery{
+			Distance: distance,
+			GeoDistanceQuery: map[string]types.GeoLocation{
+				field: types.LatLonGeoLocation{Lat: types.Float64(lat), Lon: types.Float64(lon)},
+			},
+		},
+	})
+}
+
+// GeoBoundingBox, geo_point alanı sol üst ve sağ alt köşeleri verilen dikdörtgenin içinde olan belgeleri arar
+func GeoBoundingBox(field string, topLeftLat, topLeftLon, bottomRightLat, bottomRightLon float64) Builder {
+	return Raw(&types.Query{
+		GeoBoundingBox: &types.GeoBoundingBoxQuery{
+			GeoBoundingBoxQuery: map[string]types.GeoBounds{
+				field: types.TopLeftBottomRightGeoBounds{
+					TopLeft:     types.LatLonGeoLocation{Lat: types.Float64(topLeftLat), Lon: types.Float64(topLeftLon)},
+					BottomRight: types.LatLonGeoLocation{Lat: types.Float64(bottomRightLat), Lon: types.Float64(bottomRightLon)},
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,281 @@
+package q
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/operator"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/textquerytype"
+)
+
+// MatchQuery, tam metin match sorgusu oluşturucusudur
+type MatchQuery struct {
+	field string
+	query types.MatchQuery
+}
+
+// Match, alanda analiz edilmiş tam metin araması yapar
+func Match(field, text string) *MatchQuery {
+	return &MatchQuery{field: field, query: types.MatchQuery{Query: text}}
+}
+
+// Operator, kelimeler arasındaki ilişkiyi belirler: "and" ya da "or"
+func (m *MatchQuery) Operator(op string) *MatchQuery {
+	m.query.Operator = &operator.Operator{Name: op}
+	return m
+}
+
+// Fuzziness, yazım hatası toleransını belirler (örn: "AUTO")
+func (m *MatchQuery) Fuzziness(fuzziness string) *MatchQuery {
+	m.query.Fuzziness = fuzziness
+	return m
+}
+
+// Analyzer, sorgu metnini analiz edecek analyzer'ı belirler
+func (m *MatchQuery) Analyzer(analyzer string) *MatchQuery {
+	m.query.Analyzer = stringPtr(analyzer)
+	return m
+}
+
+// Boost, sorgunun skora etkisini çarpar
+func (m *MatchQuery) Boost(boost float32) *MatchQuery {
+	m.query.Boost = float32Ptr(boost)
+	return m
+}
+
+func (m *MatchQuery) Build() (*types.Query, error) {
+	return &types.Query{Match: map[string]types.MatchQuery{m.field: m.query}}, nil
+}
+
+// MatchPhraseQuery, kelimelerin sırasıyla geçmesini arayan match_phrase oluşturucusudur
+type MatchPhraseQuery struct {
+	field string
+	query types.MatchPhraseQuery
+}
+
+// MatchPhrase, ifadenin alanda birebir (sırasıyla) geçtiği belgeleri arar
+func MatchPhrase(field, text string) *MatchPhraseQuery {
+	return &MatchPhraseQuery{field: field, query: types.MatchPhraseQuery{Query: text}}
+}
+
+// Slop, kelimeler arasında izin verilen en fazla mesafedir
+func (m *MatchPhraseQuery) Slop(slop int) *MatchPhraseQuery {
+	m.query.Slop = &slop
+	return m
+}
+
+func (m *MatchPhraseQuery) Boost(boost float32) *MatchPhraseQuery {
+	m.query.Boost = float32Ptr(boost)
+	return m
+}
+
+func (m *MatchPhraseQuery) Build() (*types.Query, error) {
+	return &types.Query{MatchPhrase: map[string]types.MatchPhraseQuery{m.field: m.query}}, nil
+}
+
+// MultiMatchQuery, birden fazla alanda arama yapan multi_match oluşturucusudur
+type MultiMatchQuery struct {
+	query types.MultiMatchQuery
+}
+
+// MultiMatch, metni verilen alanlarda arar; alanlara ağırlık verilebilir (örn: "name^3")
+func MultiMatch(text string, fields ...string) *MultiMatchQuery {
+	return &MultiMatchQuery{query: types.MultiMatchQuery{Query: text, Fields: fields}}
+}
+
+// Type, multi_match tipini belirler: best_fields, most_fields, cross_fields, phrase ...
+func (m *MultiMatchQuery) Type(t string) *MultiMatchQuery {
+	m.query.Type = &textquerytype.TextQueryType{Name: t}
+	return m
+}
+
+func (m *MultiMatchQuery) Fuzziness(fuzziness string) *MultiMatchQuery {
+	m.query.Fuzziness = fuzziness
+	return m
+}
+
+func (m *MultiMatchQuery) Operator(op string) *MultiMatchQuery {
+	m.query.Operator = &operator.Operator{Name: op}
+	return m
+}
+
+func (m *MultiMatchQuery) Boost(boost float32) *MultiMatchQuery {
+	m.query.Boost = float32Ptr(boost)
+	return m
+}
+
+func (m *MultiMatchQuery) Build() (*types.Query, error) {
+	query := m.query
+	return &types.Query{MultiMatch: &query}, nil
+}
+
+// TermQuery, analiz edilmeyen birebir değer eşleşmesi yapan term oluşturucusudur
+type TermQuery struct {
+	field string
+	query types.TermQuery
+}
+
+// Term, keyword, sayı, tarih gibi alanlarda birebir değer araması yapar
+func Term(field string, value interface{}) *TermQuery {
+	return &TermQuery{field: field, query: types.TermQuery{Value: value}}
+}
+
+// CaseInsensitive, keyword alanlarda büyük/küçük harf duyarsız eşleşme yapar
+func (t *TermQuery) CaseInsensitive() *TermQuery {
+	caseInsensitive := true
+	t.query.CaseInsensitive = &caseInsensitive
+	return t
+}
+
+func (t *TermQuery) Boost(boost float32) *TermQuery {
+	t.query.Boost = float32Ptr(boost)
+	return t
+}
+
+func (t *TermQuery) Build() (*types.Query, error) {
+	return &types.Query{Term: map[string]types.TermQuery{t.field: t.query}}, nil
+}
+
+// TermsQuery, değerlerden herhangi biriyle eşleşen terms oluşturucusudur
+type TermsQuery struct {
+	field  string
+	values []types.FieldValue
+	boost  *float32
+}
+
+// Terms, alanın verilen değerlerden biri olduğu belgeleri arar
+func Terms(field string, values ...interface{}) *TermsQuery {
+	fieldValues := make([]types.FieldValue, 0, len(values))
+	for _, v := range values {
+		fieldValues = append(fieldValues, v)
+	}
+	return &TermsQuery{field: field, values: fieldValues}
+}
+
+func (t *TermsQuery) Boost(boost float32) *TermsQuery {
+	t.boost = float32Ptr(boost)
+	return t
+}
+
+func (t *TermsQuery) Build() (*types.Query, error) {
+	return &types.Query{
+		Terms: &types.TermsQuery{
+			Boost:      t.boost,
+			TermsQuery: map[string]types.TermsQueryField{t.field: t.values},
+		},
+	}, nil
+}
+
+// RangeQuery, sayı, tarih ve string aralıkları için range oluşturucusudur
+type RangeQuery struct {
+	field string
+	query types.UntypedRangeQuery
+	err   error
+}
+
+// Range, alan için aralık sorgusu başlatır: q.Range("price").Gte(1000).Lte(2000)
+// Tarih alanlarında date math kullanılabilir: q.Range("create_date").Gte("now-7d/d")
+func Range(field string) *RangeQuery {
+	return &RangeQuery{field: field}
+}
+
+func (r *RangeQuery) Gt(v interface{}) *RangeQuery {
+	return r.bound(&r.query.Gt, v)
+}
+
+func (r *RangeQuery) Gte(v interface{}) *RangeQuery {
+	return r.bound(&r.query.Gte, v)
+}
+
+func (r *RangeQuery) Lt(v interface{}) *RangeQuery {
+	return r.bound(&r.query.Lt, v)
+}
+
+func (r *RangeQuery) Lte(v interface{}) *RangeQuery {
+	return r.bound(&r.query.Lte, v)
+}
+
+// Format, tarih değerlerinin biçimini belirler (örn: "yyyy-MM-dd")
+func (r *RangeQuery) Format(format string) *RangeQuery {
+	r.query.Format = stringPtr(format)
+	return r
+}
+
+// TimeZone, tarih değerleri ve date math yuvarlaması için saat dilimidir (örn: "Europe/Istanbul")
+func (r *RangeQuery) TimeZone(timeZone string) *RangeQuery {
+	r.query.TimeZone = stringPtr(timeZone)
+	return r
+}
+
+func (r *RangeQuery) Boost(boost float32) *RangeQuery {
+	r.query.Boost = float32Ptr(boost)
+	return r
+}
+
+func (r *RangeQuery) Build() (*types.Query, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	return &types.Query{Range: map[string]types.RangeQuery{r.field: r.query}}, nil
+}
+
+// bound, aralık sınırını JSON'a çevirip yazar; çevrilemeyen değerin hatası Build'den döner
+func (r *RangeQuery) bound(target *json.RawMessage, v interface{}) *RangeQuery {
+	value, err := rawValue(v)
+	if err != nil {
+		if r.err == nil {
+			r.err = fmt.Errorf("q: range %s: %w", r.field, err)
+		}
+		return r
+	}
+	*target = value
+	return r
+}
+
+// Exists, alanın indekslenmiş bir değeri olan belgeleri arar
+func Exists(field string) Builder {
+	return Raw(&types.Query{Exists: &types.ExistsQuery{Field: field}})
+}
+
+// PrefixQuery, verilen önekle başlayan terimleri arayan prefix oluşturucusudur
+type PrefixQuery struct {
+	field string
+	query types.PrefixQuery
+}
+
+// Prefix, alanın verilen önekle başladığı belgeleri arar
+func Prefix(field, value string) *PrefixQuery {
+	return &PrefixQuery{field: field, query: types.PrefixQuery{Value: value}}
+}
+
+func (p *PrefixQuery) CaseInsensitive() *PrefixQuery {
+	caseInsensitive := true
+	p.query.CaseInsensitive = &caseInsensitive
+	return p
+}
+
+func (p *PrefixQuery) Build() (*types.Query, error) {
+	return &types.Query{Prefix: map[string]types.PrefixQuery{p.field: p.query}}, nil
+}
+
+// WildcardQuery, * ve ? joker karakterleriyle arama yapan wildcard oluşturucusudur
+type WildcardQuery struct {
+	field string
+	query types.WildcardQuery
+}
+
+// Wildcard, alanda joker karakterli desen araması yapar (örn: "ni*e")
+func Wildcard(field, pattern string) *WildcardQuery {
+	return &WildcardQuery{field: field, query: types.WildcardQuery{Value: stringPtr(pattern)}}
+}
+
+func (w *WildcardQuery) CaseInsensitive() *WildcardQuery {
+	caseInsensitive := true
+	w.query.CaseInsensitive = &caseInsensitive
+	return w
+}
+
+func (w *WildcardQuery) Build() (*types.Query, error) {
+	return &types.Query{Wildcard: map[string]types.WildcardQuery{w.field: w.query}}, nil
+}
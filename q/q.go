@@ -0,0 +1,105 @@
+// Package q, types.Query üreten küçük ve zincirlenebilir bir Query DSL oluşturucusudur.
+//
+//	query := q.Bool().
+//		Filter(q.Term("brand", "Nike"), q.Range("price").Gte(1000)).
+//		Must(q.Match("name", "air max"))
+//
+//	built, err := query.Build()
+//	if err != nil {
+//		return err
+//	}
+//	res, err := client.Search().Index("products").
+//		Request(&search.Request{Query: built}).
+//		Do(ctx)
+//
+// Zincir sırasında oluşan hatalar (örn: JSON'a çevrilemeyen bir range sınırı) oluşturucuda saklanır
+// ve Build'den döner; bool, nested ve function_score iç sorguların hatalarını da taşır.
+//
+// Ham JSON string'leri ve iç içe map[string]interface{} sorguları yerine kullanılır;
+// üretilen sorgu q.JSON ile JSON'a çevrilip kolayca karşılaştırılabilir.
+package q
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+)
+
+// Builder, types.Query üreten her sorgu oluşturucusunun ortak arayüzüdür
+type Builder interface {
+	Build() (*types.Query, error)
+}
+
+// JSON, sorguyu Elasticsearch'e gönderilecek JSON gövdesine çevirir
+func JSON(b Builder) ([]byte, error) {
+	query, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(query)
+}
+
+// Raw, hazır bir types.Query'yi Builder olarak kullanmayı sağlar
+func Raw(query *types.Query) Builder {
+	return rawBuilder{query: query}
+}
+
+type rawBuilder struct {
+	query *types.Query
+}
+
+func (r rawBuilder) Build() (*types.Query, error) {
+	return r.query, nil
+}
+
+// MatchAll, tüm belgelerle eşleşen sorguyu döner
+func MatchAll() Builder {
+	return Raw(&types.Query{MatchAll: types.NewMatchAllQuery()})
+}
+
+// MatchNone, hiçbir belgeyle eşleşmeyen sorguyu döner
+func MatchNone() Builder {
+	return Raw(&types.Query{MatchNone: types.NewMatchNoneQuery()})
+}
+
+// build, builder listesini types.Query listesine çevirir; nil builder'lar atlanır
+func build(builders []Builder) ([]types.Query, error) {
+	queries := make([]types.Query, 0, len(builders))
+	for _, b := range builders {
+		query, err := buildOne(b)
+		if err != nil {
+			return nil, err
+		}
+		if query != nil {
+			queries = append(queries, *query)
+		}
+	}
+	return queries, nil
+}
+
+// buildOne, tek bir builder'ı çalıştırır; nil builder nil sorgu döner
+func buildOne(b Builder) (*types.Query, error) {
+	if b == nil {
+		return nil, nil
+	}
+	return b.Build()
+}
+
+// rawValue, range sınırları gibi serbest değerleri JSON'a çevirir
+// Değerler sayı, string, bool ya da time.Time olmalıdır; NaN, kanal gibi çevrilemeyen değerler hata döner
+func rawValue(v interface{}) (json.RawMessage, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("%T değeri JSON'a çevrilemedi: %w", v, err)
+	}
+	return data, nil
+}
+
+func float32Ptr(v float32) *float32 {
+	return &v
+}
+
+func stringPtr(v string) *string {
+	return &v
+}
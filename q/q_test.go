@@ -0,0 +1,176 @@
+package q
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder Builder
+		want    string
+	}{
+		{
+			"match",
+			Match("name", "air max").Operator("and").Fuzziness("AUTO").Boost(2),
+			`{"match":{"name":{"boost":2,"fuzziness":"AUTO","operator":"and","query":"air max"}}}`,
+		},
+		{
+			"match_phrase",
+			MatchPhrase("name", "air max").Slop(1),
+			`{"match_phrase":{"name":{"query":"air max","slop":1}}}`,
+		},
+		{
+			"multi_match",
+			MultiMatch("ayakkabi", "category", "category.folded").Type("most_fields"),
+			`{"multi_match":{"fields":["category","category.folded"],"query":"ayakkabi","type":"most_fields"}}`,
+		},
+		{
+			"term",
+			Term("brand.keyword", "Nike").CaseInsensitive(),
+			`{"term":{"brand.keyword":{"case_insensitive":true,"value":"Nike"}}}`,
+		},
+		{
+			"terms",
+			Terms("brand.keyword", "Nike", "Adidas").Boost(1.5),
+			`{"terms":{"boost":1.5,"brand.keyword":["Nike","Adidas"]}}`,
+		},
+		{
+			"range",
+			Range("price").Gte(1000).Lt(2000.5),
+			`{"range":{"price":{"gte":1000,"lt":2000.5}}}`,
+		},
+		{
+			"tarih aralığı",
+			Range("create_date").Gte(time.Date(2024, 9, 24, 0, 0, 0, 0, time.UTC)).Lte("now/d").TimeZone("Europe/Istanbul"),
+			`{"range":{"create_date":{"gte":"2024-09-24T00:00:00Z","lte":"now/d","time_zone":"Europe/Istanbul"}}}`,
+		},
+		{
+			"exists",
+			Exists("rating"),
+			`{"exists":{"field":"rating"}}`,
+		},
+		{
+			"prefix ve wildcard",
+			Bool().Should(Prefix("name", "air").CaseInsensitive(), Wildcard("brand", "ni*e")),
+			`{"bool":{"should":[{"prefix":{"name":{"case_insensitive":true,"value":"air"}}},{"wildcard":{"brand":{"value":"ni*e"}}}]}}`,
+		},
+		{
+			"bool",
+			Bool().
+				Filter(Term("brand.keyword", "Nike"), Range("price").Gte(1000)).
+				Must(Match("name", "air max")).
+				MustNot(Term("stock_count", 0)).
+				Should(nil, Term("color", "Siyah")).
+				MinimumShouldMatch(1),
+			`{"bool":{` +
+				`"filter":[{"term":{"brand.keyword":{"value":"Nike"}}},{"range":{"price":{"gte":1000}}}],` +
+				`"minimum_should_match":1,` +
+				`"must":[{"match":{"name":{"query":"air max"}}}],` +
+				`"must_not":[{"term":{"stock_count":{"value":0}}}],` +
+				`"should":[{"term":{"color":{"value":"Siyah"}}}]}}`,
+		},
+		{
+			"nested",
+			Nested("reviews", Range("reviews.rating").Gte(4)).ScoreMode("max"),
+			`{"nested":{"path":"reviews","query":{"range":{"reviews.rating":{"gte":4}}},"score_mode":"max"}}`,
+		},
+		{
+			// nil sorgu, path altında en az bir nesnesi olan belgelerle eşleşir
+			"nested nil sorgu",
+			Nested("reviews", nil),
+			`{"nested":{"path":"reviews","query":{"match_all":{}}}}`,
+		},
+		{
+			"function_score",
+			FunctionScore(Match("name", "nike")).
+				FieldValueFactor("sold_count", 1.2, "log1p", 0).
+				Weight(Term("is_available", true), 2).
+				ScoreMode("sum").
+				BoostMode("multiply"),
+			`{"function_score":{"boost_mode":"multiply","functions":[` +
+				`{"field_value_factor":{"factor":1.2,"field":"sold_count","missing":0,"modifier":"log1p"}},` +
+				`{"filter":{"term":{"is_available":{"value":true}}},"weight":2}],` +
+				`"query":{"match":{"name":{"query":"nike"}}},"score_mode":"sum"}}`,
+		},
+		{
+			// nil sorgu gönderilmez; Elasticsearch tüm belgeleri puanlar
+			"function_score nil sorgu",
+			FunctionScore(nil).Weight(nil, 3),
+			`{"function_score":{"functions":[{"weight":3}]}}`,
+		},
+		{
+			"geo_distance",
+			GeoDistance("location", 41.01, 28.97, "10km"),
+			`{"geo_distance":{"distance":"10km","location":{"lat":41.01,"lon":28.97}}}`,
+		},
+		{
+			"match_all",
+			MatchAll(),
+			`{"match_all":{}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := JSON(tt.builder)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !jsonEqual(t, got, []byte(tt.want)) {
+				t.Errorf("\n%s\nolmalı:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildErrors(t *testing.T) {
+	invalid := Range("price").Gte(math.NaN())
+
+	tests := []struct {
+		name    string
+		builder Builder
+	}{
+		{"range", invalid},
+		{"bool", Bool().Filter(Term("brand", "Nike"), invalid)},
+		{"iç içe bool", Bool().Must(Bool().Should(invalid))},
+		{"nested", Nested("reviews", invalid)},
+		{"function_score", FunctionScore(invalid)},
+		{"function_score filtresi", FunctionScore(MatchAll()).Weight(invalid, 2)},
+	}
+	for _, tt := range tests {
+		query, err := tt.builder.Build()
+		if err == nil {
+			t.Errorf("%s: hata bekleniyordu, sorgu %+v", tt.name, query)
+			continue
+		}
+		var unsupported *json.UnsupportedValueError
+		if !errors.As(err, &unsupported) || !strings.Contains(err.Error(), "range price") {
+			t.Errorf("%s: hata %v", tt.name, err)
+		}
+	}
+
+	// Hata bool'a eklenen diğer sorguları etkilemez; ilk hata saklanır
+	if _, err := Bool().Must(Match("name", "nike")).Filter(Range("rating").Lt(make(chan int)), invalid).Build(); err == nil ||
+		!strings.Contains(err.Error(), "range rating") {
+		t.Errorf("ilk hata dönmeli: %v", err)
+	}
+}
+
+func jsonEqual(t *testing.T, a, b []byte) bool {
+	t.Helper()
+	var x, y interface{}
+	if err := json.Unmarshal(a, &x); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(b, &y); err != nil {
+		t.Fatal(err)
+	}
+	left, _ := json.Marshal(x)
+	right, _ := json.Marshal(y)
+	return string(left) == string(right)
+}
@@ -0,0 +1,432 @@
+/*
+Package retry, Elasticsearch istekleri için ortak bir tekrar deneme politikası sağlar:
+429/502/503/504 yanıtlarında ve bağlantı hatalarında üstel bekleme (exponential backoff) ve jitter ile tekrar dener
+
+İstemcinin kendi RetryOnStatus/MaxRetries/RetryBackoff ayarları her isteği aynı şekilde tekrar denediği için
+kullanılmaz; ID'siz bir POST /my_index/_doc isteği 504 aldığında belge yazılmış olabilir ve körü körüne
+tekrar denemek aynı belgeyi iki kez oluşturur. Bu paketteki Transport isteğin idempotent olup olmadığına bakar:
+
+429 ve bağlantı kurulamadı (dial) hatalarında istek işlenmemiştir, her istek tekrar denenir.
+502/503/504 ve diğer bağlantı hatalarında sadece idempotent istekler (GET, PUT, DELETE, ID'li belge
+yazma, _search gibi okuma uç noktaları) tekrar denenir
+
+	client, err := elasticsearch.NewTypedClient(retry.Configure(elasticsearch.Config{
+		Addresses: []string{"http://localhost:9200"},
+	}))
+
+Tek bir çağrı için politika context ile değiştirilir:
+
+	ctx := retry.WithPolicy(context.Background(), retry.Policy{MaxRetries: 10, ...})
+	ctx = retry.Idempotent(ctx) // örn: tüm belgeleri ID'li bir _bulk isteği
+	ctx, attempts := retry.Track(ctx)
+*/
+package retry
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8"
+)
+
+// Policy, tekrar deneme politikasıdır
+type Policy struct {
+	MaxRetries     int           // ilk denemeden sonra en fazla kaç kez tekrar denenir; 0 tekrar denemeyi kapatır
+	InitialBackoff time.Duration // ilk tekrar öncesi en uzun bekleme
+	MaxBackoff     time.Duration // bekleme süresinin üst sınırı
+	RetryOnStatus  []int         // tekrar denenecek HTTP durumları
+}
+
+// DefaultPolicy, tüm örneklerin kullandığı varsayılan politikadır
+// 5 tekrar, en fazla 0.1s, 0.2s, 0.4s, 0.8s, 1.6s bekleme (jitter ile bunların arasında rastgele)
+var DefaultPolicy = Policy{
+	MaxRetries:     5,
+	InitialBackoff: 100 * time.Millisecond,
+	MaxBackoff:     10 * time.Second,
+	RetryOnStatus:  []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout},
+}
+
+// Backoff, attempt'inci (1'den başlar) tekrar öncesi beklenecek süreyi döner
+// "Full jitter": 0 ile min(MaxBackoff, InitialBackoff*2^(attempt-1)) arasında rastgele bir süre;
+// aynı anda hata alan istemcilerin aynı anda tekrar denemesini önler
+func (p Policy) Backoff(attempt int) time.Duration {
+	ceiling := p.InitialBackoff
+	for i := 1; i < attempt && ceiling < p.MaxBackoff; i++ {
+		ceiling *= 2
+	}
+	if ceiling > p.MaxBackoff {
+		ceiling = p.MaxBackoff
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
+
+func (p Policy) retriesStatus(status int) bool {
+	for _, s := range p.RetryOnStatus {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+type contextKey int
+
+const (
+	policyKey contextKey = iota
+	idempotentKey
+	attemptsKey
+)
+
+// WithPolicy, bu context ile yapılan isteklerde varsayılan yerine policy'yi kullanır
+func WithPolicy(ctx context.Context, policy Policy) context.Context {
+	return context.WithValue(ctx, policyKey, policy)
+}
+
+// Disable, bu context ile yapılan istekleri hiç tekrar denemez
+func Disable(ctx context.Context) context.Context {
+	return WithPolicy(ctx, Policy{})
+}
+
+// Idempotent, isteği tekrar gönderilmesi güvenli olarak işaretler (örn: tüm belgeleri ID'li bir _bulk isteği)
+func Idempotent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, idempotentKey, true)
+}
+
+// Track, bu context ile yapılan son isteğin kaç kez gönderildiğini (ilk deneme dahil) yazan bir sayaç ekler
+func Track(ctx context.Context) (context.Context, *int) {
+	attempts := new(int)
+	return context.WithValue(ctx, attemptsKey, attempts), attempts
+}
+
+// Transport, istekleri politikaya göre tekrar deneyen http.RoundTripper'dır
+type Transport struct {
+	Next    http.RoundTripper // nil ise http.DefaultTransport
+	Policy  Policy
+	Metrics *Metrics // nil ise metrik toplanmaz
+}
+
+// Configure, cfg'yi bu paketin Transport'u ve DefaultPolicy ile tekrar deneyecek şekilde ayarlar
+// İstemcinin kendi tekrar denemesi kapatılır; aynı istek iki katmanda birden tekrar denenmez
+func Configure(cfg elasticsearch.Config) elasticsearch.Config {
+	cfg.Transport = &Transport{Next: cfg.Transport, Policy: DefaultPolicy, Metrics: DefaultMetrics}
+	cfg.DisableRetry = true
+	return cfg
+}
+
+// RoundTrip, isteği gönderir ve gerekirse tekrar dener
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	ctx := req.Context()
+	policy := t.Policy
+	if override, ok := ctx.Value(policyKey).(Policy); ok {
+		policy = override
+	}
+	idempotent := isIdempotent(req)
+	attempts, _ := ctx.Value(attemptsKey).(*int)
+
+	// Gövde her denemede yeniden gönderilebilsin diye bir kez okunur
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	t.Metrics.request()
+	for attempt := 0; ; attempt++ {
+		if attempts != nil {
+			*attempts = attempt + 1
+		}
+
+		try := req
+		if body != nil {
+			try = req.Clone(ctx)
+			try.Body = io.NopCloser(bytes.NewReader(body))
+			try.GetBody = func() (io.ReadCloser, error) {
+				return io.NopCloser(bytes.NewReader(body)), nil
+			}
+		}
+		res, err := next.RoundTrip(try)
+
+		reason, retryable := shouldRetry(policy, res, err, idempotent)
+		if reason == "" {
+			return res, err
+		}
+		if !retryable {
+			t.Metrics.skipped(reason)
+			return res, err
+		}
+		if attempt >= policy.MaxRetries {
+			t.Metrics.exhausted(reason)
+			return res, err
+		}
+
+		wait := policy.Backoff(attempt + 1)
+		if res != nil {
+			if after := retryAfter(res); after > wait {
+				wait = after
+			}
+			// Bağlantının tekrar kullanılabilmesi için yanıt gövdesi okunup kapatılır
+			io.Copy(io.Discard, res.Body)
+			res.Body.Close()
+		}
+		t.Metrics.retry(reason)
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// shouldRetry, yanıtın tekrar denenebilir bir hata olup olmadığını döner
+// reason boşsa yanıt başarılı ya da tekrar denemeyle düzelmeyecek bir hatadır;
+// retryable false ise hata tekrar denenebilir türdendir ama istek idempotent değildir
+func shouldRetry(policy Policy, res *http.Response, err error, idempotent bool) (reason string, retryable bool) {
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return "", false
+		}
+		// Bağlantı kurulamadıysa istek gönderilmemiştir
+		var opErr *net.OpError
+		if errors.As(err, &opErr) && opErr.Op == "dial" {
+			return "connection refused", true
+		}
+		return "connection", idempotent
+	}
+
+	if !policy.retriesStatus(res.StatusCode) {
+		return "", false
+	}
+	reason = strconv.Itoa(res.StatusCode)
+	// 429'da istek reddedilmiştir ve işlenmemiştir
+	if res.StatusCode == http.StatusTooManyRequests {
+		return reason, true
+	}
+	return reason, idempotent
+}
+
+// readEndpoints, POST ile çağrılsalar da veriyi değiştirmeyen ya da tekrarı zararsız olan uç noktalardır
+// _pit ve _async_search listede değildir: tekrarlanan istek kapatılmayan ikinci bir point-in-time ya da
+// saklanan bir arama sonucu açar ve keep_alive boyunca kümede kaynak tutar
+var readEndpoints = []string{
+	"_search", "_msearch", "_count", "_mget", "_field_caps", "_explain", "_validate", "_render",
+	"_termvectors", "_mtermvectors", "_simulate", "_analyze", "_refresh", "_flush", "_forcemerge",
+	"_sql",
+}
+
+// conditionalParams, yazmayı koşullu yapan parametrelerdir; koşul ilk denemede sağlanıp yazma yapıldıysa
+// tekrar deneme 409 alır
+var conditionalParams = []string{"if_seq_no", "if_primary_term", "version"}
+
+// isIdempotent, isteğin tekrar gönderilmesinin ikinci bir yan etki oluşturup oluşturmayacağını belirler
+func isIdempotent(req *http.Request) bool {
+	if marked, ok := req.Context().Value(idempotentKey).(bool); ok && marked {
+		return true
+	}
+
+	segments := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	if createsOnce(req, segments) {
+		return false
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	case http.MethodPost:
+	default:
+		return false
+	}
+
+	for i, segment := range segments {
+		for _, endpoint := range readEndpoints {
+			if segment == endpoint {
+				return true
+			}
+		}
+		// POST /index/_doc/<id> aynı belgenin üzerine yazar
+		if segment == "_doc" && i+1 < len(segments) && segments[i+1] != "" {
+			return true
+		}
+	}
+	// ID'siz _doc, _update (betik sayaç artırabilir), _bulk, _update_by_query, _rollover...
+	return false
+}
+
+// createsOnce, sadece bir kez başarılı olabilen yazmaları tanır: ilk deneme yazıp yanıtı kaybolursa
+// tekrar deneme 409 ya da "already exists" alır ve başarılı yazma hata olarak görünür
+// (ve DLQ'ya version_conflict olarak düşer)
+//
+//	PUT /index/_create/<id>, ?op_type=create     belge oluşturma
+//	?if_seq_no=, ?if_primary_term=, ?version=    koşullu yazma
+//	PUT /<index>, PUT /_data_stream/<ad>         indeks ve data stream oluşturma
+//	PUT|POST /_snapshot/<repo>/<snapshot>        snapshot oluşturma
+func createsOnce(req *http.Request, segments []string) bool {
+	if req.Method != http.MethodPut && req.Method != http.MethodPost {
+		return false
+	}
+
+	query := req.URL.Query()
+	if query.Get("op_type") == "create" {
+		return true
+	}
+	for _, param := range conditionalParams {
+		if query.Has(param) {
+			return true
+		}
+	}
+
+	for _, segment := range segments {
+		if segment == "_create" {
+			return true
+		}
+	}
+	switch {
+	case req.Method == http.MethodPut && len(segments) == 1 && segments[0] != "" && !strings.HasPrefix(segments[0], "_"):
+		return true
+	case req.Method == http.MethodPut && len(segments) == 2 && segments[0] == "_data_stream":
+		return true
+	case len(segments) == 3 && segments[0] == "_snapshot":
+		return true
+	}
+	return false
+}
+
+// retryAfter, 429/503 yanıtındaki Retry-After başlığını (saniye) okur
+func retryAfter(res *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(res.Header.Get("Retry-After"))
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// Metrics, tekrar deneme sayaçlarıdır; eşzamanlı kullanıma uygundur
+type Metrics struct {
+	mu        sync.Mutex
+	requests  int64
+	retries   map[string]int64 // neden -> tekrar sayısı
+	exhausts  map[string]int64 // neden -> deneme sınırına ulaşıp vazgeçilen istek sayısı
+	skips     map[string]int64 // neden -> idempotent olmadığı için tekrar denenmeyen istek sayısı
+	startedAt time.Time
+}
+
+// DefaultMetrics, Configure ile oluşturulan tüm istemcilerin paylaştığı sayaçlardır
+var DefaultMetrics = NewMetrics()
+
+// NewMetrics, boş sayaçlar oluşturur
+func NewMetrics() *Metrics {
+	return &Metrics{
+		retries:   make(map[string]int64),
+		exhausts:  make(map[string]int64),
+		skips:     make(map[string]int64),
+		startedAt: time.Now(),
+	}
+}
+
+func (m *Metrics) request() {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	m.requests++
+	m.mu.Unlock()
+}
+
+func (m *Metrics) retry(reason string) {
+	if m != nil {
+		m.add(m.retries, reason)
+	}
+}
+
+func (m *Metrics) exhausted(reason string) {
+	if m != nil {
+		m.add(m.exhausts, reason)
+	}
+}
+
+func (m *Metrics) skipped(reason string) {
+	if m != nil {
+		m.add(m.skips, reason)
+	}
+}
+
+func (m *Metrics) add(counters map[string]int64, reason string) {
+	m.mu.Lock()
+	counters[reason]++
+	m.mu.Unlock()
+}
+
+// Snapshot, sayaçların anlık kopyasıdır
+type Snapshot struct {
+	Requests  int64
+	Retries   map[string]int64
+	Exhausted map[string]int64
+	Skipped   map[string]int64
+	Since     time.Time
+}
+
+// Snapshot, sayaçların anlık kopyasını döner
+func (m *Metrics) Snapshot() Snapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return Snapshot{
+		Requests:  m.requests,
+		Retries:   copyCounters(m.retries),
+		Exhausted: copyCounters(m.exhausts),
+		Skipped:   copyCounters(m.skips),
+		Since:     m.startedAt,
+	}
+}
+
+func copyCounters(counters map[string]int64) map[string]int64 {
+	copied := make(map[string]int64, len(counters))
+	for reason, n := range counters {
+		copied[reason] = n
+	}
+	return copied
+}
+
+func (s Snapshot) String() string {
+	return fmt.Sprintf("istek: %d, tekrar: %s, vazgeçilen: %s, idempotent olmadığı için tekrar denenmeyen: %s",
+		s.Requests, formatCounters(s.Retries), formatCounters(s.Exhausted), formatCounters(s.Skipped))
+}
+
+func formatCounters(counters map[string]int64) string {
+	if len(counters) == 0 {
+		return "0"
+	}
+	reasons := make([]string, 0, len(counters))
+	for reason := range counters {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+
+	parts := make([]string, 0, len(reasons))
+	for _, reason := range reasons {
+		parts = append(parts, fmt.Sprintf("%s=%d", reason, counters[reason]))
+	}
+	return strings.Join(parts, " ")
+}
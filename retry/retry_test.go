@@ -0,0 +1,277 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	policy := Policy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	ceilings := []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second, // MaxBackoff ile sınırlanır
+		time.Second,
+	}
+	for i, ceiling := range ceilings {
+		attempt := i + 1
+		for n := 0; n < 100; n++ {
+			if wait := policy.Backoff(attempt); wait < 0 || wait > ceiling {
+				t.Fatalf("Backoff(%d) = %s, 0 ile %s arasında olmalı", attempt, wait, ceiling)
+			}
+		}
+	}
+
+	if wait := (Policy{}).Backoff(3); wait != 0 {
+		t.Errorf("bekleme süresi olmayan politikada Backoff = %s, 0 olmalı", wait)
+	}
+}
+
+func TestIsIdempotent(t *testing.T) {
+	tests := []struct {
+		method string
+		path   string
+		want   bool
+	}{
+		{http.MethodGet, "/products/_doc/1", true},
+		{http.MethodHead, "/products", true},
+		{http.MethodPut, "/products/_doc/1", true},
+		{http.MethodDelete, "/products/_doc/1", true},
+		{http.MethodPost, "/products/_search", true},
+		{http.MethodPost, "/_msearch", true},
+		{http.MethodPost, "/products/_count", true},
+		{http.MethodPost, "/products/_doc/1", true},
+		// Sadece bir kez başarılı olabilen yazmalar: tekrar deneme 409 ya da "already exists" alır
+		{http.MethodPost, "/products/_create/1", false},
+		{http.MethodPut, "/products/_create/1", false},
+		{http.MethodPut, "/products/_doc/1?op_type=create", false},
+		{http.MethodPut, "/products/_doc/1?if_seq_no=3&if_primary_term=1", false},
+		{http.MethodPut, "/products", false},
+		{http.MethodPut, "/products-v2", false},
+		{http.MethodPut, "/_data_stream/app-events", false},
+		{http.MethodPut, "/_snapshot/backups/nightly-1", false},
+		{http.MethodPost, "/_snapshot/backups/nightly-1", false},
+		{http.MethodPut, "/_snapshot/backups", true},
+		{http.MethodPut, "/products/_mapping", true},
+		{http.MethodPut, "/_index_template/products", true},
+		{http.MethodDelete, "/products", true},
+		{http.MethodPost, "/products/_doc", false},
+		{http.MethodPost, "/products/_doc/", false},
+		{http.MethodPost, "/products/_update/1", false},
+		{http.MethodPost, "/_bulk", false},
+		{http.MethodPost, "/products/_update_by_query", false},
+		{http.MethodPost, "/products/_pit", false},
+		{http.MethodPost, "/products/_async_search", false},
+		{http.MethodPost, "/events/_rollover", false},
+		{http.MethodPatch, "/products/_doc/1", false},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		if got := isIdempotent(req); got != tt.want {
+			t.Errorf("isIdempotent(%s %s) = %v, %v olmalı", tt.method, tt.path, got, tt.want)
+		}
+	}
+
+	// Idempotent ile işaretlenen istek yoldan bağımsız olarak tekrar denenebilir
+	req := httptest.NewRequest(http.MethodPost, "/_bulk", nil)
+	req = req.WithContext(Idempotent(req.Context()))
+	if !isIdempotent(req) {
+		t.Error("Idempotent ile işaretlenmiş _bulk isteği idempotent sayılmalı")
+	}
+}
+
+func TestShouldRetry(t *testing.T) {
+	dialErr := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	readErr := &net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")}
+
+	tests := []struct {
+		name          string
+		status        int
+		err           error
+		idempotent    bool
+		wantReason    string
+		wantRetryable bool
+	}{
+		{"başarılı", 200, nil, false, "", false},
+		{"404 tekrar denenmez", 404, nil, true, "", false},
+		{"429 her zaman", 429, nil, false, "429", true},
+		{"503 idempotent", 503, nil, true, "503", true},
+		{"503 idempotent değil", 503, nil, false, "503", false},
+		{"504 idempotent değil", 504, nil, false, "504", false},
+		{"dial hatası her zaman", 0, dialErr, false, "connection refused", true},
+		{"okuma hatası idempotent", 0, readErr, true, "connection", true},
+		{"okuma hatası idempotent değil", 0, readErr, false, "connection", false},
+		{"iptal", 0, context.Canceled, true, "", false},
+		{"zaman aşımı", 0, context.DeadlineExceeded, true, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res *http.Response
+			if tt.err == nil {
+				res = &http.Response{StatusCode: tt.status}
+			}
+			reason, retryable := shouldRetry(DefaultPolicy, res, tt.err, tt.idempotent)
+			if reason != tt.wantReason || retryable != tt.wantRetryable {
+				t.Errorf("shouldRetry = (%q, %v), (%q, %v) olmalı", reason, retryable, tt.wantReason, tt.wantRetryable)
+			}
+		})
+	}
+}
+
+// statusServer, sırayla statuses'taki durumları, bittiğinde 200 döner ve gelen gövdeleri kaydeder
+func statusServer(t *testing.T, statuses ...int) (*httptest.Server, *[]string) {
+	var (
+		mu     sync.Mutex
+		bodies []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(body))
+		n := len(bodies)
+		mu.Unlock()
+
+		if n <= len(statuses) {
+			w.WriteHeader(statuses[n-1])
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &bodies
+}
+
+func testTransport() *Transport {
+	return &Transport{
+		Policy: Policy{
+			MaxRetries:     3,
+			InitialBackoff: time.Millisecond,
+			MaxBackoff:     time.Millisecond,
+			RetryOnStatus:  DefaultPolicy.RetryOnStatus,
+		},
+		Metrics: NewMetrics(),
+	}
+}
+
+func TestTransportRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		method, path string
+		statuses     []int
+		wantStatus   int
+		wantAttempts int
+	}{
+		{"429 ID'siz POST'ta da tekrar denenir", http.MethodPost, "/products/_doc", []int{429, 429}, 200, 3},
+		{"503 ID'siz POST'ta tekrar denenmez", http.MethodPost, "/products/_doc", []int{503}, 503, 1},
+		{"503 ID'li PUT'ta tekrar denenir", http.MethodPut, "/products/_doc/1", []int{503, 502}, 200, 3},
+		{"deneme sınırında vazgeçilir", http.MethodGet, "/products/_doc/1", []int{503, 503, 503, 503, 503}, 503, 4},
+		{"400 tekrar denenmez", http.MethodPut, "/products/_doc/1", []int{400}, 400, 1},
+		// İlk deneme belgeyi yazıp 503 dönmüş olabilir; tekrar deneme 409 alır ve yazma hata gibi görünürdü
+		{"503 _create'te tekrar denenmez", http.MethodPut, "/products/_create/1", []int{503, 409}, 503, 1},
+		{"429 _create'te tekrar denenir", http.MethodPut, "/products/_create/1", []int{429}, 200, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, bodies := statusServer(t, tt.statuses...)
+			transport := testTransport()
+
+			ctx, attempts := Track(context.Background())
+			req, _ := http.NewRequestWithContext(ctx, tt.method, server.URL+tt.path, strings.NewReader(`{"name":"Air Max"}`))
+			res, err := transport.RoundTrip(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			res.Body.Close()
+
+			if res.StatusCode != tt.wantStatus {
+				t.Errorf("durum %d, %d olmalı", res.StatusCode, tt.wantStatus)
+			}
+			if *attempts != tt.wantAttempts || len(*bodies) != tt.wantAttempts {
+				t.Errorf("%d deneme (%d istek), %d olmalı", *attempts, len(*bodies), tt.wantAttempts)
+			}
+			// Gövde her denemede aynen gönderilmeli
+			for i, body := range *bodies {
+				if body != `{"name":"Air Max"}` {
+					t.Errorf("%d. denemenin gövdesi %q", i+1, body)
+				}
+			}
+		})
+	}
+}
+
+func TestTransportMetrics(t *testing.T) {
+	server, _ := statusServer(t, 429, 200, 503)
+	transport := testTransport()
+
+	// İlk istek 429 alıp tekrar denenir, ikincisi 503 alır ve ID'siz POST olduğu için tekrar denenmez
+	for _, path := range []string{"/products/_doc/1", "/products/_doc"} {
+		req, _ := http.NewRequest(http.MethodPost, server.URL+path, strings.NewReader(`{}`))
+		res, err := transport.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+	}
+
+	snapshot := transport.Metrics.Snapshot()
+	if snapshot.Requests != 2 {
+		t.Errorf("Requests = %d, 2 olmalı", snapshot.Requests)
+	}
+	if len(snapshot.Retries) != 1 || snapshot.Retries["429"] != 1 {
+		t.Errorf("Retries = %v, sadece 429=1 olmalı", snapshot.Retries)
+	}
+	if len(snapshot.Skipped) != 1 || snapshot.Skipped["503"] != 1 {
+		t.Errorf("Skipped = %v, sadece 503=1 olmalı", snapshot.Skipped)
+	}
+	if len(snapshot.Exhausted) != 0 {
+		t.Errorf("Exhausted = %v, boş olmalı", snapshot.Exhausted)
+	}
+}
+
+func TestTransportPolicyOverride(t *testing.T) {
+	server, bodies := statusServer(t, 503, 503)
+	transport := testTransport()
+
+	req, _ := http.NewRequestWithContext(Disable(context.Background()), http.MethodGet, server.URL+"/products/_doc/1", nil)
+	res, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != 503 || len(*bodies) != 1 {
+		t.Errorf("Disable ile %d istek gönderildi (durum %d), 1 olmalı", len(*bodies), res.StatusCode)
+	}
+}
+
+func TestTransportStopsWhenContextDone(t *testing.T) {
+	server, bodies := statusServer(t, 503, 503, 503, 503)
+	transport := testTransport()
+	transport.Policy.InitialBackoff = time.Minute
+	transport.Policy.MaxBackoff = time.Minute
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/products/_doc/1", nil)
+
+	start := time.Now()
+	_, err := transport.RoundTrip(req)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("hata %v, context.DeadlineExceeded olmalı", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("bekleme context bitince kesilmedi: %s", elapsed)
+	}
+	if len(*bodies) > 1 {
+		t.Errorf("%d istek gönderildi, 1 olmalı", len(*bodies))
+	}
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/scriptlanguage"
+)
+
+/*
+	Search Template Kayıt Defteri:
+	Ürün sorguları Go'da her istekte yeniden kurulmak yerine mustache şablonu olarak _scripts altında saklanır
+	Her sürüm değişmez bir id ile saklanır:  products_by_price_category-v1, products_by_price_category-v2 ...
+	Servisler ise sürümsüz id'yi çalıştırır:   products_by_price_category
+	Arama ekibi yeni bir sürüm yükleyip ActivateSearchTemplate ile etkinleştirerek
+	Go servislerini yeniden deploy etmeden sorguyu değiştirebilir, gerekirse eski sürüme geri dönebilir
+*/
+
+// SearchTemplateDef, sürümlü bir search template tanımıdır
+type SearchTemplateDef struct {
+	Name    string
+	Version int
+	Source  string // mustache şablonu
+}
+
+// VersionID, şablonun bu sürümünün _scripts altındaki değişmez id'sidir
+func (d SearchTemplateDef) VersionID() string {
+	return searchTemplateVersionID(d.Name, d.Version)
+}
+
+func searchTemplateVersionID(name string, version int) string {
+	return fmt.Sprintf("%s-v%d", name, version)
+}
+
+// ProductsByPriceCategoryParams, products_by_price_category şablonunun parametreleridir
+type ProductsByPriceCategoryParams struct {
+	MinPrice float64 `json:"min_price"`
+	MaxPrice float64 `json:"max_price"`
+	Category string  `json:"category"`
+	Size     int     `json:"size,omitempty"`
+}
+
+// MostSoldProductsParams, products_most_sold şablonunun parametreleridir
+type MostSoldProductsParams struct {
+	Size int `json:"size,omitempty"`
+}
+
+// ProductSearchTemplates, servis açılışında kaydedilen ürün şablonlarıdır
+// {{#toJson}} bloğu, parametreyi JSON olarak (tırnak ve kaçış karakterleriyle) yazar
+var ProductSearchTemplates = []SearchTemplateDef{
+	{
+		Name:    "products_by_price_category",
+		Version: 1,
+		Source: `{
+  "query": {
+    "bool": {
+      "filter": [
+        {"range": {"price": {"gte": {{min_price}}, "lte": {{max_price}}}}}
+      ],
+      "must": [
+        {"multi_match": {"query": {{#toJson}}category{{/toJson}}, "fields": ["category", "category.folded"], "type": "most_fields"}}
+      ]
+    }
+  },
+  "sort": [{"price": "asc"}],
+  "size": {{size}}{{^size}}20{{/size}}
+}`,
+	},
+	{
+		Name:    "products_most_sold",
+		Version: 1,
+		Source: `{
+  "query": {"match_all": {}},
+  "sort": [{"sold_count": "desc"}],
+  "size": {{size}}{{^size}}10{{/size}}
+}`,
+	},
+}
+
+// PutSearchTemplate, şablonun sürümünü değişmez id'siyle kaydeder
+// Aynı sürüm farklı içerikle zaten kayıtlıysa hata döner; şablon değiştiyse sürüm artırılmalıdır
+func PutSearchTemplate(client *elasticsearch.TypedClient, def SearchTemplateDef) error {
+	ctx := context.Background()
+
+	existing, err := getStoredScript(client, def.VersionID())
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		if existing.Source != def.Source {
+			return fmt.Errorf("%s zaten farklı bir içerikle kayıtlı, şablon sürümünü artırın", def.VersionID())
+		}
+		return nil
+	}
+
+	_, err = client.PutScript(def.VersionID()).
+		Script(&types.StoredScript{Lang: scriptlanguage.Mustache, Source: def.Source}).
+		Do(ctx)
+	return err
+}
+
+// ActivateSearchTemplate, şablonun verilen sürümünü servislerin çalıştırdığı sürümsüz id'ye kopyalar
+func ActivateSearchTemplate(client *elasticsearch.TypedClient, name string, version int) error {
+	ctx := context.Background()
+
+	script, err := getStoredScript(client, searchTemplateVersionID(name, version))
+	if err != nil {
+		return err
+	}
+	if script == nil {
+		return fmt.Errorf("%s bulunamadı", searchTemplateVersionID(name, version))
+	}
+
+	_, err = client.PutScript(name).
+		Script(&types.StoredScript{Lang: scriptlanguage.Mustache, Source: script.Source}).
+		Do(ctx)
+	return err
+}
+
+// RegisterSearchTemplates, tanımlı sürümleri kaydeder ve henüz etkin sürümü olmayan şablonları etkinleştirir
+// Arama ekibinin etkinleştirdiği sürüm, servis yeniden başlatıldığında ezilmez
+func RegisterSearchTemplates(client *elasticsearch.TypedClient, defs ...SearchTemplateDef) error {
+	for _, def := range defs {
+		if err := PutSearchTemplate(client, def); err != nil {
+			return err
+		}
+
+		active, err := getStoredScript(client, def.Name)
+		if err != nil {
+			return err
+		}
+		if active == nil {
+			if err := ActivateSearchTemplate(client, def.Name, def.Version); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// RenderSearchTemplate, şablonu verilen parametrelerle _render/template üzerinden çalıştırmadan derler
+// Şablon değişikliklerini test etmek için kullanılır; dönen değer Elasticsearch'e gidecek arama gövdesidir
+func RenderSearchTemplate(client *elasticsearch.TypedClient, id string, params interface{}) (json.RawMessage, error) {
+	ctx := context.Background()
+
+	templateParams, err := searchTemplateParams(params)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.RenderSearchTemplate().
+		Id(id).
+		Params(templateParams).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(res.TemplateOutput)
+}
+
+// SearchTemplate, kayıtlı şablonu products indeksinde çalıştırır ve ürünleri döner
+func SearchTemplate(client *elasticsearch.TypedClient, id string, params interface{}) (*ProductSearchResult, error) {
+	ctx := context.Background()
+
+	templateParams, err := searchTemplateParams(params)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.SearchTemplate().
+		Index("products").
+		Id(id).
+		Params(templateParams).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return decodeProductHits(res.Hits)
+}
+
+// searchTemplateParams, tipli parametre struct'ını (ya da map'i) şablon parametrelerine çevirir
+func searchTemplateParams(params interface{}) (map[string]json.RawMessage, error) {
+	if params == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+
+	var templateParams map[string]json.RawMessage
+	if err := json.Unmarshal(data, &templateParams); err != nil {
+		return nil, fmt.Errorf("şablon parametreleri bir JSON nesnesi olmalı: %w", err)
+	}
+	return templateParams, nil
+}
+
+// getStoredScript, kayıtlı script'i döner; script yoksa nil döner
+func getStoredScript(client *elasticsearch.TypedClient, id string) (*types.StoredScript, error) {
+	ctx := context.Background()
+
+	res, err := client.GetScript(id).Do(ctx)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !res.Found {
+		return nil, nil
+	}
+	return res.Script, nil
+}
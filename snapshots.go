@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/SadikSunbul/Go-Elasticsearch/indexops"
+	"github.com/elastic/go-elasticsearch/v8"
+)
+
+/*
+	Ürün Yedekleri:
+	Yerel tek node'lu kümede denemek için elasticsearch.yml'e path.repo eklenip node yeniden başlatılır:
+
+		path.repo: ["/var/backups/elasticsearch"]
+
+	Docker'da: -e path.repo=/var/backups/elasticsearch -v $(pwd)/backups:/var/backups/elasticsearch
+
+	go run . snapshots backup                 -> products* indekslerinin snapshot'ını alır ve bitmesini bekler
+	go run . snapshots list                   -> snapshot'ları listeler
+	go run . snapshots restore <snapshot>     -> indeksleri restored_<ad> olarak geri yükler
+	go run . snapshots delete <snapshot>      -> snapshot'ı siler
+*/
+
+const (
+	// snapshotRepository, ürün yedeklerinin yazıldığı fs repository'sidir
+	snapshotRepository = "product_backups"
+	// snapshotLocation, path.repo'nun ilk dizinine göre çözülen göreli konumdur
+	snapshotLocation = "products"
+)
+
+// BackupProducts, products* indekslerinin tarihli bir snapshot'ını alır ve bitmesini bekler
+func BackupProducts(client *elasticsearch.TypedClient) (*indexops.SnapshotProgress, error) {
+	if _, err := indexops.RegisterFSRepository(client, snapshotRepository, snapshotLocation); err != nil {
+		return nil, err
+	}
+
+	// Snapshot adları küçük harf olmalıdır
+	snapshot := "products-" + time.Now().UTC().Format("2006.01.02-150405")
+	if _, err := indexops.CreateSnapshot(client, snapshotRepository, snapshot, "products*"); err != nil {
+		return nil, err
+	}
+
+	return indexops.WaitForSnapshot(client, snapshotRepository, snapshot, time.Second, 10*time.Minute,
+		func(progress indexops.SnapshotProgress) {
+			fmt.Println(progress)
+		})
+}
+
+// RestoreProducts, snapshot'taki indeksleri çalışan indekslere dokunmadan restored_ önekiyle geri yükler
+func RestoreProducts(client *elasticsearch.TypedClient, snapshot string) (*indexops.Result, error) {
+	return indexops.RestoreSnapshot(client, snapshotRepository, snapshot, indexops.RestoreOptions{
+		RenamePattern:     "(.+)",
+		RenameReplacement: "restored_$1",
+		Wait:              true,
+	})
+}
+
+// runSnapshotsCommand, "snapshots backup|list|restore|delete" komutlarını çalıştırır
+func runSnapshotsCommand(client *elasticsearch.TypedClient, args []string) error {
+	usage := fmt.Errorf("kullanım: snapshots backup | list | restore <snapshot> | delete <snapshot>")
+	if len(args) == 0 {
+		return usage
+	}
+
+	switch {
+	case args[0] == "backup" && len(args) == 1:
+		_, err := BackupProducts(client)
+		return err
+	case args[0] == "list" && len(args) == 1:
+		snapshots, err := indexops.ListSnapshots(client, snapshotRepository)
+		if err != nil {
+			return err
+		}
+		for _, snapshot := range snapshots {
+			fmt.Println(snapshot)
+		}
+		return nil
+	case args[0] == "restore" && len(args) == 2:
+		result, err := RestoreProducts(client, args[1])
+		if err != nil {
+			return err
+		}
+		fmt.Println(result)
+		return nil
+	case args[0] == "delete" && len(args) == 2:
+		result, err := indexops.DeleteSnapshot(client, snapshotRepository, args[1])
+		if err != nil {
+			return err
+		}
+		fmt.Println(result)
+		return nil
+	default:
+		return usage
+	}
+}
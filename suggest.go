@@ -0,0 +1,219 @@
+package main
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/suggestmode"
+)
+
+/*
+	"Bunu mu demek istediniz?" Önerileri:
+	term suggester sorgudaki her kelime için indeksteki yakın terimleri önerir
+	phrase suggester tüm ifade için düzeltilmiş cümleler önerir
+	Collate açıkken phrase önerileri arka planda sorgulanır ve sadece sonuç döndüren öneriler bırakılır;
+	term önerilerinden kurulan düzeltme sorgulanmadığı için sadece doğrulanmış bir phrase önerisiyle aynıysa gösterilir
+	Öneriler, Türkçe küçültme dışında analiz yapılmayan .suggest alt alanından üretilir
+	(kök bulma ve ascii folding uygulanmış alanlar "ayakkab" gibi okunamaz öneriler üretirdi)
+*/
+
+const (
+	termSuggestionName   = "term_suggestion"
+	phraseSuggestionName = "phrase_suggestion"
+)
+
+// SuggestOptions, arama ile birlikte çalıştırılacak öneri (suggest) ayarlarıdır
+type SuggestOptions struct {
+	Field   string // öneri alanı, boşsa name.suggest
+	Term    bool   // kelime bazlı term suggester çalıştırılsın mı
+	Phrase  bool   // ifade bazlı phrase suggester çalıştırılsın mı
+	Size    int    // öneri sayısı, 0 ise 3
+	Collate bool   // sadece sonuç döndürdüğü doğrulanan öneriler bırakılsın mı
+}
+
+// Suggestion, sıralanmış tek bir düzeltme önerisidir
+type Suggestion struct {
+	Text   string  // sorgunun düzeltilmiş hali
+	Score  float64 // önerinin skoru, yüksek olan daha olası
+	Source string  // öneriyi üreten suggester: term ya da phrase
+}
+
+// suggestEntry, suggest yanıtında sorgudaki tek bir kelimenin (ya da ifadenin) önerileridir
+// typedapi, aynı suggester'ın birden fazla girdisini tek girdiye indirgediği için yanıt bu tiple çözülür
+type suggestEntry struct {
+	Text    string          `json:"text"`
+	Offset  int             `json:"offset"`
+	Length  int             `json:"length"`
+	Options []suggestOption `json:"options"`
+}
+
+type suggestOption struct {
+	Text         string  `json:"text"`
+	Score        float64 `json:"score"`
+	Freq         int64   `json:"freq"`
+	CollateMatch *bool   `json:"collate_match"`
+}
+
+// decodeRawSuggest, ham search yanıtındaki suggest bölümünü suggester adına göre çözer
+// typed_keys açık olduğunda anahtarlar "term#term_suggestion" biçimindedir, tip öneki atılır
+func decodeRawSuggest(body []byte) (map[string][]suggestEntry, error) {
+	var raw struct {
+		Suggest map[string][]suggestEntry `json:"suggest"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, err
+	}
+
+	suggest := make(map[string][]suggestEntry, len(raw.Suggest))
+	for key, entries := range raw.Suggest {
+		if _, name, found := strings.Cut(key, "#"); found {
+			key = name
+		}
+		suggest[key] = entries
+	}
+	return suggest, nil
+}
+
+// buildSuggester, sorgu metni için term ve/veya phrase suggester tanımlarını oluşturur
+func buildSuggester(text string, options *SuggestOptions) *types.Suggester {
+	if options == nil || text == "" || (!options.Term && !options.Phrase) {
+		return nil
+	}
+
+	field := options.Field
+	if field == "" {
+		field = "name." + SuggestSubField
+	}
+	size := options.Size
+	if size <= 0 {
+		size = 3
+	}
+
+	suggester := &types.Suggester{
+		Text:       &text,
+		Suggesters: map[string]types.FieldSuggester{},
+	}
+
+	if options.Term {
+		suggester.Suggesters[termSuggestionName] = types.FieldSuggester{
+			Term: &types.TermSuggester{
+				Field:       field,
+				Size:        &size,
+				SuggestMode: &suggestmode.Missing,
+			},
+		}
+	}
+
+	if options.Phrase {
+		phrase := &types.PhraseSuggester{
+			Field: field,
+			Size:  &size,
+			DirectGenerator: []types.DirectGenerator{
+				{Field: field, SuggestMode: &suggestmode.Always},
+			},
+		}
+		if options.Collate {
+			// Öneri, aynı alanda tüm kelimeleriyle eşleşen en az bir belge varsa döner
+			source := `{"match": {"{{field_name}}": {"query": "{{suggestion}}", "operator": "and"}}}`
+			prune := false
+			phrase.Collate = &types.PhraseSuggestCollate{
+				Query:  types.PhraseSuggestCollateQuery{Source: &source},
+				Params: map[string]json.RawMessage{"field_name": json.RawMessage(strconv.Quote(field))},
+				Prune:  &prune,
+			}
+		}
+		suggester.Suggesters[phraseSuggestionName] = types.FieldSuggester{Phrase: phrase}
+	}
+
+	return suggester
+}
+
+// decodeSuggestions, yanıttaki suggest bölümünü skora göre sıralanmış önerilere dönüştürür
+// collate true ise sonuç döndürdüğü doğrulanmayan öneriler bırakılmaz
+func decodeSuggestions(text string, suggest map[string][]suggestEntry, collate bool) []Suggestion {
+	var suggestions []Suggestion
+	verified := map[string]bool{}
+
+	for _, entry := range suggest[phraseSuggestionName] {
+		for _, option := range entry.Options {
+			// prune kapalıyken eşleşmeyen öneriler yanıtta hiç yer almaz, açıkken collate_match false döner
+			if option.CollateMatch != nil && !*option.CollateMatch {
+				continue
+			}
+			verified[option.Text] = true
+			suggestions = append(suggestions, Suggestion{
+				Text:   option.Text,
+				Score:  option.Score,
+				Source: "phrase",
+			})
+		}
+	}
+
+	// Term düzeltmesi collate sorgusundan geçmez; collate açıkken sadece doğrulanmış bir phrase önerisiyle aynıysa tutulur
+	if corrected, score, ok := correctWithTermSuggestions(text, suggest[termSuggestionName]); ok && (!collate || verified[corrected]) {
+		suggestions = append(suggestions, Suggestion{Text: corrected, Score: score, Source: "term"})
+	}
+
+	// Aynı metni öneren suggester'lardan skoru yüksek olan tutulur
+	best := map[string]int{}
+	var unique []Suggestion
+	for _, s := range suggestions {
+		if strings.EqualFold(s.Text, text) {
+			continue
+		}
+		if i, ok := best[s.Text]; ok {
+			if s.Score > unique[i].Score {
+				unique[i] = s
+			}
+			continue
+		}
+		best[s.Text] = len(unique)
+		unique = append(unique, s)
+	}
+
+	sort.SliceStable(unique, func(i, j int) bool {
+		return unique[i].Score > unique[j].Score
+	})
+	return unique
+}
+
+// correctWithTermSuggestions, her hatalı kelimeyi en iyi term önerisiyle değiştirerek sorguyu düzeltir
+// Düzeltilmiş sorgunun skoru, değiştirilen kelimelerin en düşük öneri skorudur
+func correctWithTermSuggestions(text string, entries []suggestEntry) (string, float64, bool) {
+	runes := []rune(text)
+	var builder strings.Builder
+	position := 0
+	score := 0.0
+	corrected := false
+
+	for _, term := range entries {
+		if len(term.Options) == 0 {
+			continue
+		}
+		// Offset ve length, Elasticsearch tarafında UTF-16 karakter olarak hesaplanır;
+		// Türkçe karakterler BMP içinde olduğundan rune ile aynıdır
+		start, end := term.Offset, term.Offset+term.Length
+		if start < position || end > len(runes) {
+			continue
+		}
+
+		best := term.Options[0]
+		builder.WriteString(string(runes[position:start]))
+		builder.WriteString(best.Text)
+		position = end
+
+		if !corrected || best.Score < score {
+			score = best.Score
+		}
+		corrected = true
+	}
+
+	if !corrected {
+		return "", 0, false
+	}
+	builder.WriteString(string(runes[position:]))
+	return builder.String(), score, true
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeSuggestions(t *testing.T) {
+	matched, unmatched := true, false
+	term := []suggestEntry{
+		{Text: "nikee", Offset: 0, Length: 5, Options: []suggestOption{{Text: "nike", Score: 0.8}}},
+		{Text: "ayakabı", Offset: 6, Length: 7, Options: []suggestOption{{Text: "ayakkabı", Score: 0.75}}},
+	}
+
+	tests := []struct {
+		name    string
+		suggest map[string][]suggestEntry
+		collate bool
+		want    []Suggestion
+	}{
+		{
+			"collate kapalı, term düzeltmesi eklenir",
+			map[string][]suggestEntry{
+				termSuggestionName:   term,
+				phraseSuggestionName: {{Options: []suggestOption{{Text: "nike ayakkabi", Score: 0.9}}}},
+			},
+			false,
+			[]Suggestion{{"nike ayakkabi", 0.9, "phrase"}, {"nike ayakkabı", 0.75, "term"}},
+		},
+		{
+			// Term düzeltmesi collate sorgusundan geçmediği için sonuç döndürmeyebilir
+			"collate açık, doğrulanmamış term düzeltmesi atılır",
+			map[string][]suggestEntry{
+				termSuggestionName:   term,
+				phraseSuggestionName: {{Options: []suggestOption{{Text: "nike ayakkabi", Score: 0.9}}}},
+			},
+			true,
+			[]Suggestion{{"nike ayakkabi", 0.9, "phrase"}},
+		},
+		{
+			"collate açık, phrase ile aynı term düzeltmesi yüksek skorla tutulur",
+			map[string][]suggestEntry{
+				termSuggestionName:   term,
+				phraseSuggestionName: {{Options: []suggestOption{{Text: "nike ayakkabı", Score: 0.5, CollateMatch: &matched}}}},
+			},
+			true,
+			[]Suggestion{{"nike ayakkabı", 0.75, "term"}},
+		},
+		{
+			// prune açıkken eşleşmeyen phrase önerileri collate_match false ile döner
+			"eşleşmeyen phrase önerisi atılır",
+			map[string][]suggestEntry{
+				phraseSuggestionName: {{Options: []suggestOption{
+					{Text: "nike ayakkabi", Score: 0.9, CollateMatch: &unmatched},
+					{Text: "nike ayakkabı", Score: 0.6, CollateMatch: &matched},
+				}}},
+			},
+			true,
+			[]Suggestion{{"nike ayakkabı", 0.6, "phrase"}},
+		},
+	}
+	for _, tt := range tests {
+		got := decodeSuggestions("nikee ayakabı", tt.suggest, tt.collate)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s:\n%+v\nolmalı:\n%+v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/SadikSunbul/Go-Elasticsearch/indexops"
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+)
+
+/*
+	Zaman Bazlı İndeks Template'leri:
+	products-2026.10 gibi aylık ürün indeksleri ve app-logs-* log indeksleri ilk yazmada otomatik oluşur
+	Shard/replica ayarları ve Türkçe analyzer'lar her indekste tekrar yazılmaz, component template olarak paylaşılır:
+
+	shared-settings   -> products, logs
+	turkish-analysis  -> products
+	products-mappings -> products   (Product struct'ından üretilir)
+	logs-mappings     -> logs
+	events-mappings   -> events   (events ILM politikası ile rollover alias'ı "events")
+
+	products template'i products-clean ingest pipeline'ını default_pipeline olarak bağlar (pipelines.go)
+
+	Tanımlardan biri değiştiğinde version artırılmalıdır; "go run . templates diff" farkları gösterir,
+	"go run . templates apply" sadece değişen template'leri yazar
+	"go run . templates bootstrap" events-000001 yazma indeksini oluşturur, "templates rollover" koşulları
+	dry-run ile kontrol eder ve ILM durumunu gösterir
+*/
+
+// eventsAlias, olay indekslerine yazılırken ve okunurken kullanılan rollover alias'ıdır
+const eventsAlias = "events"
+
+// LogEntry, app-logs-* indekslerindeki bir log kaydıdır
+type LogEntry struct {
+	Timestamp string `json:"@timestamp" es:"date"`
+	Level     string `json:"level" es:"keyword"`
+	Service   string `json:"service" es:"keyword"`
+	Message   string `json:"message" es:"text"`
+	TraceID   string `json:"trace_id" es:"keyword"`
+}
+
+// Event, events alias'ına yazılan olay kaydıdır
+type Event struct {
+	Timestamp string `json:"@timestamp" es:"date"`
+	Type      string `json:"type" es:"keyword"`
+	ProductID string `json:"product_id" es:"keyword"`
+	UserID    string `json:"user_id" es:"keyword"`
+	Quantity  int    `json:"quantity" es:"integer"`
+}
+
+// IndexTemplates, uygulamanın yönettiği tüm ingest pipeline'ları, ILM politikaları, component ve index template'lerini döner
+func IndexTemplates() (indexops.TemplateSet, error) {
+	productMapping, err := MappingFromStruct(Product{})
+	if err != nil {
+		return indexops.TemplateSet{}, err
+	}
+	logMapping, err := MappingFromStruct(LogEntry{})
+	if err != nil {
+		return indexops.TemplateSet{}, err
+	}
+	eventMapping, err := MappingFromStruct(Event{})
+	if err != nil {
+		return indexops.TemplateSet{}, err
+	}
+	warmReplicas := 0
+
+	pipeline := productPipelineName
+
+	return indexops.TemplateSet{
+		Pipelines: []indexops.Pipeline{ProductPipeline()},
+		Policies: []indexops.LifecyclePolicy{
+			{
+				// Günlük ya da 50gb'da yeni indeks, 7 gün sonra salt okunur tek segment, 30 gün sonra silme
+				Name:              "events-policy",
+				Version:           1,
+				RolloverMaxAge:    "1d",
+				RolloverMaxSize:   "50gb",
+				WarmAfter:         "7d",
+				WarmMergeSegments: 1,
+				WarmReplicas:      &warmReplicas,
+				DeleteAfter:       "30d",
+			},
+		},
+		Components: []indexops.ComponentTemplate{
+			{
+				Name:    "shared-settings",
+				Version: 1,
+				Settings: &types.IndexSettings{
+					NumberOfShards:   "1",
+					NumberOfReplicas: "1",
+				},
+			},
+			{
+				Name:     "turkish-analysis",
+				Version:  1,
+				Settings: ProductIndexSettings(),
+			},
+			{
+				Name:     "products-mappings",
+				Version:  5,
+				Mappings: productMapping,
+			},
+			{
+				Name:     "logs-mappings",
+				Version:  1,
+				Mappings: logMapping,
+			},
+			{
+				Name:     "events-mappings",
+				Version:  1,
+				Mappings: eventMapping,
+			},
+		},
+		Indices: []indexops.IndexTemplate{
+			{
+				Name:          "products",
+				Version:       2,
+				IndexPatterns: []string{"products-*"},
+				ComposedOf:    []string{"shared-settings", "turkish-analysis", "products-mappings"},
+				Priority:      200,
+				Template: &types.IndexTemplateMapping{
+					Settings: &types.IndexSettings{DefaultPipeline: &pipeline},
+				},
+			},
+			{
+				// logs-* ön eki kullanılmaz: yerleşik "logs" template'i (logs-*-*) Elastic Agent/Fleet data
+				// stream'lerine aittir ve paylaşılan bir kümede daha yüksek öncelikle ezilmemelidir
+				Name:          "app-logs",
+				Version:       2,
+				IndexPatterns: []string{"app-logs-*"},
+				ComposedOf:    []string{"shared-settings", "logs-mappings"},
+				Priority:      200,
+			},
+			{
+				// Sadece events-000001 gibi backing indeksler eşleşir, "events" alias'ı ile çakışmaz
+				Name:          "events",
+				Version:       1,
+				IndexPatterns: []string{eventsAlias + "-*"},
+				ComposedOf:    []string{"shared-settings", "events-mappings"},
+				Priority:      200,
+				Template: &types.IndexTemplateMapping{
+					Settings: indexops.LifecycleSettings("events-policy", eventsAlias),
+				},
+			},
+		},
+	}, nil
+}
+
+// runTemplatesCommand, "templates diff|apply|bootstrap|rollover" komutlarını çalıştırır
+func runTemplatesCommand(client *elasticsearch.TypedClient, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("kullanım: templates diff|apply|bootstrap|rollover")
+	}
+
+	switch args[0] {
+	case "bootstrap":
+		result, err := indexops.BootstrapWriteIndex(client, eventsAlias)
+		if err != nil {
+			return err
+		}
+		fmt.Println(result)
+		return nil
+	case "rollover":
+		return inspectRollover(client)
+	case "diff", "apply":
+	default:
+		return fmt.Errorf("kullanım: templates diff|apply|bootstrap|rollover")
+	}
+
+	templates, err := IndexTemplates()
+	if err != nil {
+		return err
+	}
+
+	var changes []indexops.TemplateChange
+	if args[0] == "apply" {
+		changes, err = templates.Apply(client)
+	} else {
+		changes, err = templates.Diff(client)
+	}
+	for _, change := range changes {
+		fmt.Println(change)
+	}
+	return err
+}
+
+// inspectRollover, events alias'ının rollover koşullarını dry-run ile kontrol eder ve backing indekslerin ILM durumunu yazar
+func inspectRollover(client *elasticsearch.TypedClient) error {
+	rollover, err := indexops.Rollover(client, eventsAlias, indexops.RolloverOptions{
+		MaxAge:  "1d",
+		MaxSize: "50gb",
+		DryRun:  true,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s: %s -> %s, rollover gerekli mi: %v\n", rollover.Alias, rollover.OldIndex, rollover.NewIndex, rollover.Conditions)
+
+	statuses, err := indexops.ExplainLifecycle(client, eventsAlias+"-*")
+	if err != nil {
+		return err
+	}
+	for _, status := range statuses {
+		fmt.Println(status)
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+package transform
+
+// DocumentImport, 09 ve 11 örneklerindeki dummy_data.json kayıtlarını (title, text, createdOn, price)
+// indekslemeden önce düzelten ve doğrulayan zincirdir
+//   - createdOn, indeksteki created_on adına taşınır
+//   - price Türkçe yazımla gelebilir ("1.250,90")
+//   - eski içe aktarıcının eklediği import_batch indekslenmez
+//   - ID title ve created_on'dan türetilir; dosya tekrar yüklendiğinde aynı belgelerin üzerine yazılır
+func DocumentImport() Chain {
+	return Chain{
+		Rename("createdOn", "created_on"),
+		ToFloat("price", TurkishNumbers),
+		Default("text", ""),
+		Drop("import_batch"),
+		Require("title", "created_on"),
+		HashID("title", "created_on"),
+	}
+}
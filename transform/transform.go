@@ -0,0 +1,354 @@
+/*
+Package transform, içe aktarılan kayıtları indekslemeden önce Go tarafında dönüştüren adım zinciridir
+
+Sunucu tarafındaki ingest pipeline'lardan farklı olarak kayıtlar Elasticsearch'e gitmeden düzeltilir
+ya da reddedilir; reddedilen kayıtlar hata nedeniyle birlikte bir dead-letter dosyasına yazılır
+
+	chain := transform.Chain{
+		transform.Rename("createdOn", "created_on"),
+		transform.ToFloat("price", transform.TurkishNumbers),
+		transform.Default("text", ""),
+		transform.Drop("internal_notes"),
+		transform.Require("title", "created_on"),
+		transform.HashID("title", "created_on"),
+	}
+	result, err := chain.Run(records, rejectsFile)
+
+Adımlar sırayla çalışır; bir adım hata dönerse kayıt reddedilir ve sonraki adımlar çalışmaz
+*/
+package transform
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// IDField, HashID'nin türettiği belge ID'sinin kayıtta tutulduğu anahtardır
+// Elasticsearch _id'yi _source içinde kabul etmediği için indekslemeden önce Split ile ayrılır
+const IDField = "_id"
+
+// Record, içe aktarılan tek bir kayıttır (örn: JSON dosyasındaki bir nesne)
+type Record map[string]interface{}
+
+// Split, kaydın ID'sini ve ID'siz _source'unu döner; ID türetilmediyse boş string döner
+func (r Record) Split() (string, Record) {
+	id, _ := r[IDField].(string)
+	source := make(Record, len(r))
+	for key, value := range r {
+		if key != IDField {
+			source[key] = value
+		}
+	}
+	return id, source
+}
+
+// Step, kaydı dönüştüren tek bir adımdır
+// nil kayıt dönerse kayıt sessizce atlanır, hata dönerse reddedilir
+type Step func(Record) (Record, error)
+
+// Chain, sırayla uygulanan adımlardır
+type Chain []Step
+
+// Apply, kaydı tüm adımlardan geçirir; girdi kaydı ve iç içe nesneleri değiştirilmez
+func (c Chain) Apply(record Record) (Record, error) {
+	current := make(Record, len(record))
+	for key, value := range record {
+		current[key] = deepCopy(value)
+	}
+
+	for _, step := range c {
+		next, err := step(current)
+		if err != nil {
+			return nil, err
+		}
+		if next == nil {
+			return nil, nil
+		}
+		current = next
+	}
+	return current, nil
+}
+
+// deepCopy, JSON'dan çözülmüş iç içe nesneleri ve dizileri kopyalar; adımlar kopyayı değiştirdiğinde
+// Run'a verilen kayıtlar (ve reddedilenlerin dead-letter'a yazılan hali) bozulmaz
+func deepCopy(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			copied[key] = deepCopy(child)
+		}
+		return copied
+	case Record:
+		copied := make(Record, len(v))
+		for key, child := range v {
+			copied[key] = deepCopy(child)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, child := range v {
+			copied[i] = deepCopy(child)
+		}
+		return copied
+	default:
+		return v
+	}
+}
+
+// Rejection, dead-letter dosyasına yazılan reddedilmiş kayıttır
+type Rejection struct {
+	Record Record `json:"record"`
+	Error  string `json:"error"`
+}
+
+// Result, Run'ın sonucudur
+type Result struct {
+	Accepted []Record // dönüştürülmüş ve indekslenmeye hazır kayıtlar
+	Dropped  int      // DropIf ile atlanan kayıt sayısı
+	Rejected int      // dead-letter dosyasına yazılan kayıt sayısı
+}
+
+func (r Result) String() string {
+	return fmt.Sprintf("%d kabul edildi, %d atlandı, %d reddedildi", len(r.Accepted), r.Dropped, r.Rejected)
+}
+
+// Run, kayıtları zincirden geçirir; reddedilenleri rejects'e satır başına bir JSON nesnesi (NDJSON) olarak yazar
+// rejects nil ise reddedilen kayıtlar sadece sayılır. Hata sadece rejects'e yazılamazsa döner
+func (c Chain) Run(records []Record, rejects io.Writer) (*Result, error) {
+	result := &Result{Accepted: make([]Record, 0, len(records))}
+
+	var encoder *json.Encoder
+	if rejects != nil {
+		encoder = json.NewEncoder(rejects)
+	}
+
+	for _, record := range records {
+		transformed, err := c.Apply(record)
+		switch {
+		case err != nil:
+			result.Rejected++
+			if encoder == nil {
+				continue
+			}
+			if err := encoder.Encode(Rejection{Record: record, Error: err.Error()}); err != nil {
+				return result, fmt.Errorf("reddedilen kayıt yazılamadı: %w", err)
+			}
+		case transformed == nil:
+			result.Dropped++
+		default:
+			result.Accepted = append(result.Accepted, transformed)
+		}
+	}
+	return result, nil
+}
+
+// ValidationError, kayıt bir doğrulama adımından geçemediğinde döner
+type ValidationError struct {
+	Field  string
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Reason)
+}
+
+// Rename, from alanını to adıyla taşır; from yoksa bir şey yapmaz, to varsa üzerine yazar
+func Rename(from, to string) Step {
+	return func(r Record) (Record, error) {
+		if value, ok := r[from]; ok {
+			delete(r, from)
+			r[to] = value
+		}
+		return r, nil
+	}
+}
+
+// NumberFormat, metin olarak gelen sayıların yazımıdır
+// Ayırıcılar açıkça verilir: "1,234" Türkçe'de 1,234 İngilizce'de 1234 olduğu için tahmin edilmez
+type NumberFormat struct {
+	DecimalSeparator   string // ondalık ayırıcı, boşsa "."
+	ThousandsSeparator string // binlik ayırıcı, boşsa binlik ayırıcılı sayılar reddedilir
+}
+
+var (
+	// PlainNumbers, sadece "1234.5" biçimini kabul eder
+	PlainNumbers = NumberFormat{}
+	// TurkishNumbers, "1.234,5" ve "1234,5" biçimlerini kabul eder
+	TurkishNumbers = NumberFormat{DecimalSeparator: ",", ThousandsSeparator: "."}
+	// EnglishNumbers, "1,234.5" ve "1234.5" biçimlerini kabul eder
+	EnglishNumbers = NumberFormat{DecimalSeparator: ".", ThousandsSeparator: ","}
+)
+
+// Parse, s'yi biçime göre ayrıştırır
+// Biçime uymayan yazımlar (örn: Türkçe biçimde "1,234.50" ya da "12.5") sessizce yanlış
+// yorumlanmak yerine hata döner
+func (f NumberFormat) Parse(s string) (float64, error) {
+	decimal := f.DecimalSeparator
+	if decimal == "" {
+		decimal = "."
+	}
+	invalid := fmt.Errorf("%q sayı biçimine uymuyor", s)
+
+	text := strings.TrimSpace(s)
+	sign := ""
+	if strings.HasPrefix(text, "-") || strings.HasPrefix(text, "+") {
+		sign, text = text[:1], text[1:]
+	}
+
+	integer, fraction, hasFraction := strings.Cut(text, decimal)
+	if hasFraction && !digits(fraction) {
+		return 0, invalid
+	}
+
+	if f.ThousandsSeparator != "" && strings.Contains(integer, f.ThousandsSeparator) {
+		groups := strings.Split(integer, f.ThousandsSeparator)
+		// İlk grup 1-3, sonrakiler tam 3 basamak olmalı: "1.234.567" geçerli, "12.5" ve "1.23" değil
+		if len(groups[0]) == 0 || len(groups[0]) > 3 {
+			return 0, invalid
+		}
+		for _, group := range groups[1:] {
+			if len(group) != 3 {
+				return 0, invalid
+			}
+		}
+		integer = strings.Join(groups, "")
+	}
+	if !digits(integer) {
+		return 0, invalid
+	}
+
+	if hasFraction {
+		integer += "." + fraction
+	}
+	return strconv.ParseFloat(sign+integer, 64)
+}
+
+// digits, s'nin boş olmadığını ve sadece rakamlardan oluştuğunu kontrol eder
+func digits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// ToFloat, alanı float64'e çevirir; metin değerler format'a göre ayrıştırılır
+// (örn: TurkishNumbers ile "100" -> 100.0, "1.234,50" -> 1234.5)
+// Alan yoksa ya da null ise bir şey yapmaz, çevrilemezse kaydı reddeder
+func ToFloat(field string, format NumberFormat) Step {
+	return func(r Record) (Record, error) {
+		value, ok := r[field]
+		if !ok || value == nil {
+			return r, nil
+		}
+
+		switch v := value.(type) {
+		case float64:
+			return r, nil
+		case int:
+			r[field] = float64(v)
+		case int64:
+			r[field] = float64(v)
+		case json.Number:
+			f, err := v.Float64()
+			if err != nil {
+				return nil, &ValidationError{Field: field, Reason: fmt.Sprintf("%q sayıya çevrilemedi", v)}
+			}
+			r[field] = f
+		case string:
+			f, err := format.Parse(v)
+			if err != nil {
+				return nil, &ValidationError{Field: field, Reason: fmt.Sprintf("%q sayıya çevrilemedi", v)}
+			}
+			r[field] = f
+		default:
+			return nil, &ValidationError{Field: field, Reason: fmt.Sprintf("%T tipi sayıya çevrilemez", value)}
+		}
+		return r, nil
+	}
+}
+
+// Default, alan yoksa, null ya da boş string ise value yazar
+func Default(field string, value interface{}) Step {
+	return func(r Record) (Record, error) {
+		if current, ok := r[field]; !ok || current == nil || current == "" {
+			r[field] = value
+		}
+		return r, nil
+	}
+}
+
+// Drop, alanları kayıttan siler
+func Drop(fields ...string) Step {
+	return func(r Record) (Record, error) {
+		for _, field := range fields {
+			delete(r, field)
+		}
+		return r, nil
+	}
+}
+
+// DropIf, predicate true dönen kayıtları reddetmeden atlar (örn: test kayıtları)
+func DropIf(predicate func(Record) bool) Step {
+	return func(r Record) (Record, error) {
+		if predicate(r) {
+			return nil, nil
+		}
+		return r, nil
+	}
+}
+
+// HashID, alanların değerlerinden kararlı bir belge ID'si türetir ve IDField'a yazar
+// Aynı kayıt tekrar içe aktarıldığında yeni belge oluşmaz, aynı belgenin üzerine yazılır
+// Adım, türetilen alanlar kesinleştikten sonra (Rename, Default, Require'dan sonra) eklenmelidir
+func HashID(fields ...string) Step {
+	return func(r Record) (Record, error) {
+		hash := sha256.New()
+		for _, field := range fields {
+			value, ok := r[field]
+			if !ok || value == nil {
+				return nil, &ValidationError{Field: field, Reason: "ID için gerekli alan eksik"}
+			}
+			// Alanlar arasına kayıtlarda geçmeyen bir ayraç koyulur: ("ab", "c") ile ("a", "bc") farklı ID üretir
+			fmt.Fprintf(hash, "%v\x1f", value)
+		}
+		r[IDField] = hex.EncodeToString(hash.Sum(nil)[:16])
+		return r, nil
+	}
+}
+
+// Require, alanların var olduğunu ve boş olmadığını doğrular
+func Require(fields ...string) Step {
+	return func(r Record) (Record, error) {
+		for _, field := range fields {
+			value, ok := r[field]
+			if !ok || value == nil {
+				return nil, &ValidationError{Field: field, Reason: "zorunlu alan eksik"}
+			}
+			if s, isString := value.(string); isString && strings.TrimSpace(s) == "" {
+				return nil, &ValidationError{Field: field, Reason: "zorunlu alan boş"}
+			}
+		}
+		return r, nil
+	}
+}
+
+// Validate, kaydı özel bir kurala göre doğrular; fn hata dönerse kayıt reddedilir
+func Validate(fn func(Record) error) Step {
+	return func(r Record) (Record, error) {
+		if err := fn(r); err != nil {
+			return nil, err
+		}
+		return r, nil
+	}
+}
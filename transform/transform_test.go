@@ -0,0 +1,92 @@
+package transform
+
+import (
+	"testing"
+)
+
+func TestNumberFormatParse(t *testing.T) {
+	tests := []struct {
+		format  NumberFormat
+		input   string
+		want    float64
+		wantErr bool
+	}{
+		{TurkishNumbers, "100", 100, false},
+		{TurkishNumbers, "1.250,90", 1250.9, false},
+		{TurkishNumbers, " 1.234.567,5 ", 1234567.5, false},
+		{TurkishNumbers, "-12,5", -12.5, false},
+		{TurkishNumbers, "1234,5", 1234.5, false},
+		{TurkishNumbers, "1.234", 1234, false},
+		{TurkishNumbers, "1,234.50", 0, true}, // İngilizce yazım
+		{TurkishNumbers, "12.5", 0, true},     // binlik grup 3 basamak değil
+		{TurkishNumbers, "1,2,3", 0, true},
+		{TurkishNumbers, "yüz lira", 0, true},
+		{EnglishNumbers, "1,234.50", 1234.5, false},
+		{EnglishNumbers, "1234.5", 1234.5, false},
+		{EnglishNumbers, "1.234,50", 0, true}, // Türkçe yazım
+		{EnglishNumbers, "1,23", 0, true},
+		{PlainNumbers, "1234.5", 1234.5, false},
+		{PlainNumbers, "1,234.5", 0, true},
+		{PlainNumbers, "", 0, true},
+		{PlainNumbers, "1.", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := tt.format.Parse(tt.input)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Parse(%q) = %v, hata bekleniyordu", tt.input, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("Parse(%q) = %v, %v; %v olmalı", tt.input, got, err, tt.want)
+		}
+	}
+}
+
+func TestApplyDoesNotModifyInput(t *testing.T) {
+	record := Record{
+		"title":  "Sample Title 1",
+		"author": map[string]interface{}{"name": "Imad"},
+		"tags":   []interface{}{"a", map[string]interface{}{"b": "c"}},
+	}
+	chain := Chain{
+		func(r Record) (Record, error) {
+			r["author"].(map[string]interface{})["name"] = "değişti"
+			r["tags"].([]interface{})[1].(map[string]interface{})["b"] = "değişti"
+			return r, nil
+		},
+	}
+	if _, err := chain.Apply(record); err != nil {
+		t.Fatal(err)
+	}
+	if name := record["author"].(map[string]interface{})["name"]; name != "Imad" {
+		t.Errorf("girdi kaydındaki iç içe nesne değişti: %v", name)
+	}
+	if b := record["tags"].([]interface{})[1].(map[string]interface{})["b"]; b != "c" {
+		t.Errorf("girdi kaydındaki dizi değişti: %v", b)
+	}
+}
+
+func TestDocumentImport(t *testing.T) {
+	records := []Record{
+		{"title": "Sample Title 4", "createdOn": "2024-09-24", "price": "1.250,90", "import_batch": "b-1"},
+		{"createdOn": "2024-09-24", "price": "10"},
+		{"title": "Bozuk Fiyat", "createdOn": "2024-09-24", "price": "1,250.90"},
+	}
+	result, err := DocumentImport().Run(records, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Accepted) != 1 || result.Rejected != 2 {
+		t.Fatalf("sonuç %s, 1 kabul 2 red olmalı", result)
+	}
+
+	id, source := result.Accepted[0].Split()
+	if id == "" || source["price"] != 1250.9 || source["created_on"] != "2024-09-24" || source["text"] != "" {
+		t.Errorf("dönüşmüş kayıt %q %v", id, source)
+	}
+	if _, ok := source["import_batch"]; ok {
+		t.Error("import_batch silinmedi")
+	}
+}